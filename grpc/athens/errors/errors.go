@@ -1,442 +0,0 @@
-package errors
-
-import (
-	"errors"
-	"fmt"
-	"github.com/go-kit/kit/log/level"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-	"io"
-	"net/http"
-	"path"
-	"runtime"
-	"strings"
-)
-
-// Kind enums
-const (
-	KindNotFound       = http.StatusNotFound
-	KindBadRequest     = http.StatusBadRequest
-	KindUnexpected     = http.StatusInternalServerError
-	KindAlreadyExists  = http.StatusConflict
-	KindRateLimit      = http.StatusTooManyRequests
-	KindNotImplemented = http.StatusNotImplemented
-	KindRedirect       = http.StatusMovedPermanently
-)
-
-// Error is an Athens system error.
-// It carries information and behavior
-// as to what caused this error so that
-// callers can implement logic around it.
-type Error struct {
-	// Kind categories Athens errors into a smaller
-	// subset of errors. This way we can generalize
-	// what an error really is: such as "not found",
-	// "bad request", etc. The official categories
-	// are HTTP status code but the ones we use are
-	// imported into this package.
-	Kind       int
-	Op         Op
-	CustomerID C
-	Err        error
-	Severity   level.Value
-	GrpcCode   *codes.Code
-	GrpcMsg    GM
-	*stack
-}
-
-// Error returns the underlying error's
-// string message. The logger takes care
-// of filling out the stack levels and
-// extra information.
-func (e Error) Error() string {
-	return e.Err.Error()
-}
-
-// Format implements a custom formatter to achieve printing of `stack` when `%+v` is used as a formatting verb
-func (e Error) Format(s fmt.State, verb rune) {
-	switch verb {
-	case 'v':
-		if s.Flag('+') {
-			io.WriteString(s, e.Error())
-			e.stack.Format(s, verb)
-			return
-		}
-		fallthrough
-	case 's':
-		io.WriteString(s, e.Error())
-	case 'q':
-		fmt.Fprintf(s, "%q", e.Error())
-	}
-}
-
-func (e Error) GRPCStatus() *status.Status {
-	c := GrpcCode(e)
-	m := GrpcMsg(e)
-	if c == nil || m == "" {
-		return nil
-	}
-	return status.New(*c, string(m))
-}
-
-// Is is a shorthand for checking an error against a kind.
-func Is(err error, kind int) bool {
-	if err == nil {
-		return false
-	}
-	return Kind(err) == kind
-}
-
-// Op describes any independent function or
-// method in Athens. A series of operations
-// forms a more readable stack trace.
-type Op string
-
-func (o Op) String() string {
-	return string(o)
-}
-
-// C represents a customerID
-type C string
-
-// GM represents a GrpcMsg
-type GM string
-
-// E is a helper function to construct an Error type
-// Operation always comes first, module path and version
-// come second, they are optional. Args must have at least
-// an error or a string to describe what exactly went wrong.
-// You can optionally pass a Logrus severity to indicate
-// the log level of an error based on the context it was constructed in.
-func E(args ...interface{}) Error {
-	e := Error{
-		stack: callers(),
-	}
-	if len(args) == 0 {
-		msg := "errors.E called with 0 args"
-		_, file, line, ok := runtime.Caller(1)
-		if ok {
-			msg = fmt.Sprintf("%v - %v:%v", msg, file, line)
-		}
-		e.Err = errors.New(msg)
-	}
-	for _, a := range args {
-		switch a := a.(type) {
-		case error:
-			e.Err = a
-
-			// replace e.stack with the inner-most stack that exists
-			if b, ok := e.Err.(Error); ok {
-				e.stack = innerStack(b)
-			}
-		case string:
-			e.Err = errors.New(a)
-		case C:
-			e.CustomerID = a
-		case GM:
-			e.GrpcMsg = a
-		case Op:
-			e.Op = a
-		case codes.Code:
-			e.GrpcCode = &a
-		case level.Value:
-			e.Severity = a
-		case int:
-			e.Kind = a
-		}
-	}
-	if e.Err == nil {
-		e.Err = errors.New(KindText(e))
-	}
-	if e.Op == "" {
-		if pc, _, line, ok := runtime.Caller(1); ok {
-			f := Frame(pc)
-			name := runtime.FuncForPC(f.pc()).Name()
-			e.Op = Op(fmt.Sprintf("%s:%d", funcname(name), line))
-		}
-	}
-	return e
-}
-
-// Severity returns the log level of an error
-// if none exists, then the level is Error because
-// it is an unexpected.
-func Severity(err error) level.Value {
-	e, ok := err.(Error)
-	if !ok {
-		return level.ErrorValue()
-	}
-
-	// if there's no severity (0 is Panic level in logrus
-	// which we should not use since cloud providers only have
-	// debug, info, warn, and error) then look for the
-	// child's severity.
-	// TODO: figure out how to do this check using go-kit level
-	//if e.Severity < level.ErrorValue() {
-	//	return Severity(e.Err)
-	//}
-
-	return e.Severity
-}
-
-// Expect is a helper that returns an Info level
-// if the error has the expected kind, otherwise
-// it returns an Error level.
-func Expect(err error, kinds ...int) level.Value {
-	for _, kind := range kinds {
-		if Kind(err) == kind {
-			return level.InfoValue()
-		}
-	}
-	return level.ErrorValue()
-}
-
-func CustomerID(err error) C {
-	e, ok := err.(Error)
-	if !ok {
-		return ""
-	}
-
-	if e.CustomerID != "" {
-		return e.CustomerID
-	}
-
-	return CustomerID(e.Err)
-}
-
-func GrpcCode(err error) *codes.Code {
-	e, ok := err.(Error)
-	if !ok {
-		return nil
-	}
-
-	if e.GrpcCode != nil {
-		return e.GrpcCode
-	}
-
-	return GrpcCode(e.Err)
-}
-
-func GrpcMsg(err error) GM {
-	e, ok := err.(Error)
-	if !ok {
-		return ""
-	}
-
-	if e.GrpcMsg != "" {
-		return e.GrpcMsg
-	}
-
-	return GrpcMsg(e.Err)
-}
-
-// Kind recursively searches for the
-// first error kind it finds.
-func Kind(err error) int {
-	e, ok := err.(Error)
-	if !ok {
-		return KindUnexpected
-	}
-
-	if e.Kind != 0 {
-		return e.Kind
-	}
-
-	return Kind(e.Err)
-}
-
-// KindText returns a friendly string
-// of the Kind type. Since we use http
-// status codes to represent error kinds,
-// this method just deferrs to the net/http
-// text representations of statuses.
-func KindText(err error) string {
-	return http.StatusText(Kind(err))
-}
-
-// Ops aggregates the error's operation
-// with all the embedded errors' operations.
-// This way you can construct a queryable
-// stack trace.
-func Ops(err Error) []Op {
-	ops := []Op{err.Op}
-	for {
-		embeddedErr, ok := err.Err.(Error)
-		if !ok {
-			break
-		}
-
-		ops = append(ops, embeddedErr.Op)
-		err = embeddedErr
-	}
-
-	return ops
-}
-
-func OpsText(err Error) string {
-	ops := string(err.Op)
-	for {
-		embeddedErr, ok := err.Err.(Error)
-		if !ok {
-			break
-		}
-
-		ops += fmt.Sprintf(": %s", embeddedErr.Op)
-		err = embeddedErr
-	}
-
-	return ops
-}
-
-func innerStack(err Error) *stack {
-	stack := err.stack
-	for {
-		embeddedErr, ok := err.Err.(Error)
-		if !ok {
-			break
-		}
-
-		stack = embeddedErr.stack
-		err = embeddedErr
-	}
-
-	return stack
-}
-
-// Frame represents a program counter inside a stack frame.
-type Frame uintptr
-
-// pc returns the program counter for this frame;
-// multiple frames may have the same PC value.
-func (f Frame) pc() uintptr { return uintptr(f) - 1 }
-
-// file returns the full path to the file that contains the
-// function for this Frame's pc.
-func (f Frame) file() string {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return "unknown"
-	}
-	file, _ := fn.FileLine(f.pc())
-	return file
-}
-
-// line returns the line number of source code of the
-// function for this Frame's pc.
-func (f Frame) line() int {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return 0
-	}
-	_, line := fn.FileLine(f.pc())
-	return line
-}
-
-// Format formats the frame according to the fmt.Formatter interface.
-//
-//    %s    source file
-//    %d    source line
-//    %n    function name
-//    %v    equivalent to %s:%d
-//
-// Format accepts flags that alter the printing of some verbs, as follows:
-//
-//    %+s   function name and path of source file relative to the compile time
-//          GOPATH separated by \n\t (<funcname>\n\t<path>)
-//    %+v   equivalent to %+s:%d
-func (f Frame) Format(s fmt.State, verb rune) {
-	switch verb {
-	case 's':
-		switch {
-		case s.Flag('+'):
-			pc := f.pc()
-			fn := runtime.FuncForPC(pc)
-			if fn == nil {
-				io.WriteString(s, "unknown")
-			} else {
-				file, _ := fn.FileLine(pc)
-				fmt.Fprintf(s, "%s\n\t%s", fn.Name(), file)
-			}
-		default:
-			io.WriteString(s, path.Base(f.file()))
-		}
-	case 'd':
-		fmt.Fprintf(s, "%d", f.line())
-	case 'n':
-		name := runtime.FuncForPC(f.pc()).Name()
-		io.WriteString(s, funcname(name))
-	case 'v':
-		f.Format(s, 's')
-		io.WriteString(s, ":")
-		f.Format(s, 'd')
-	}
-}
-
-// funcname removes the path prefix component of a function's name reported by func.Name().
-func funcname(name string) string {
-	i := strings.LastIndex(name, "/")
-	name = name[i+1:]
-	i = strings.Index(name, ".")
-	return name[i+1:]
-}
-
-// stack represents a stack of program counters.
-type stack []uintptr
-
-func (s *stack) Format(st fmt.State, verb rune) {
-	switch verb {
-	case 'v':
-		switch {
-		case st.Flag('+'):
-			for _, pc := range *s {
-				f := Frame(pc)
-				fmt.Fprintf(st, "\n%+v", f)
-			}
-		}
-	}
-}
-
-// StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
-type StackTrace []Frame
-
-// Format formats the stack of Frames according to the fmt.Formatter interface.
-//
-//    %s	lists source files for each Frame in the stack
-//    %v	lists the source file and line number for each Frame in the stack
-//
-// Format accepts flags that alter the printing of some verbs, as follows:
-//
-//    %+v   Prints filename, function, and line number for each Frame in the stack.
-func (st StackTrace) Format(s fmt.State, verb rune) {
-	switch verb {
-	case 'v':
-		switch {
-		case s.Flag('+'):
-			for _, f := range st {
-				fmt.Fprintf(s, "\n%+v", f)
-			}
-		case s.Flag('#'):
-			fmt.Fprintf(s, "%#v", []Frame(st))
-		default:
-			fmt.Fprintf(s, "%v", []Frame(st))
-		}
-	case 's':
-		fmt.Fprintf(s, "%s", []Frame(st))
-	}
-}
-
-func (s *stack) StackTrace() StackTrace {
-	f := make([]Frame, len(*s))
-	for i := 0; i < len(f); i++ {
-		f[i] = Frame((*s)[i])
-	}
-	return f
-}
-
-func callers() *stack {
-	const depth = 32
-	var pcs [depth]uintptr
-	n := runtime.Callers(3, pcs[:])
-	var st stack = pcs[0:n]
-	return &st
-}
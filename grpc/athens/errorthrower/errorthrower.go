@@ -1,7 +1,7 @@
 package errorthrower
 
 import (
-	"github.com/jwenz723/errhandling/grpc/athens/errors"
+	"github.com/jwenz723/errhandling/pkg/errors"
 	"google.golang.org/grpc/codes"
 )
 
@@ -3,16 +3,16 @@ package main
 import (
 	"context"
 	grpc_logging "github.com/grpc-ecosystem/go-grpc-middleware/logging"
-	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
-	"github.com/jwenz723/errhandling/grpc/athens/errors"
 	"github.com/jwenz723/errhandling/grpc/athens/errorthrower"
 	"github.com/jwenz723/errhandling/pb"
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/ordercodes"
 	"go.uber.org/zap"
 )
 
 var _ pb.OrdersServer = &grpcServer{}
 
-type grpcServer struct {}
+type grpcServer struct{}
 
 // GrpcLoggingDecider specifies which methods should have their request/response parameters logged
 // by the grpc logging interceptor. Returning false indicates logging should be suppressed.
@@ -27,15 +27,31 @@ func (s *grpcServer) GrpcLoggingDecider() grpc_logging.ServerPayloadLoggingDecid
 
 func (s *grpcServer) NewOrder(ctx context.Context, req *pb.NewOrderRequest) (*pb.NewOrderReply, error) {
 	const op = errors.Op("NewOrder")
+	if req.CustomerID == "" {
+		return &pb.NewOrderReply{}, errors.E(op, ordercodes.CustomerRequired)
+	}
+	ctx = errors.WithFields(ctx, errors.CustomerIDField.V(req.CustomerID))
+
 	err := errorthrower.SomeError()
 	if err != nil {
-		e := errors.E(op, err, errors.C(req.CustomerID))
-		ctxzap.AddFields(ctx,
-			zap.String("Error.Ops", errors.OpsText(e)),
-			zap.String("Error.Kind", errors.KindText(e)),
-			zap.String("Error.CustomerID", string(errors.CustomerID(e))))
-		return &pb.NewOrderReply{}, e
+		return &pb.NewOrderReply{}, errors.EC(ctx, op, err)
 	}
 
 	return &pb.NewOrderReply{OrderID: "my order id"}, nil
-}
\ No newline at end of file
+}
+
+// errorFields returns the zap fields describing err, including
+// every value attached to it with errors.Field after redaction.
+func errorFields(err error) []zap.Field {
+	fields := []zap.Field{
+		zap.String("Error.Ops", errors.OpsText(err)),
+		zap.String("Error.Kind", errors.KindText(err)),
+		zap.String("Error.Code", errors.CodeOf(err).String()),
+		zap.Bool("Error.Retryable", errors.Retryable(err)),
+		zap.String("Error.Fingerprint", errors.Fingerprint(err)),
+	}
+	for _, kv := range errors.RedactedFields(err) {
+		fields = append(fields, zap.Any("Error."+kv.Field.String(), kv.Value))
+	}
+	return fields
+}
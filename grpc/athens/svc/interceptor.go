@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/requestid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// errorFieldsUnaryServerInterceptor returns a unary server interceptor that adds the
+// errorFields of any error returned by the handler to the request's ctxzap logger.
+// It must be chained after grpc_zap.UnaryServerInterceptor so that the fields end up
+// on the line logged when the call finishes.
+func errorFieldsUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			ctxzap.AddFields(ctx, errorFields(err)...)
+		}
+		return resp, err
+	}
+}
+
+// requestIDUnaryServerInterceptor returns a unary server interceptor that adds the
+// request ID set by requestid.UnaryServerInterceptor to the request's ctxzap logger.
+// It must be chained after grpc_zap.UnaryServerInterceptor for the same reason as
+// errorFieldsUnaryServerInterceptor.
+func requestIDUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if id, ok := requestid.FromContext(ctx); ok {
+			ctxzap.AddFields(ctx, zap.String("request_id", id))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// redactErrorsUnaryServerInterceptor returns a unary server interceptor that wraps
+// the errors returned by the handler with errors.Redacted, so that grpc_zap, which
+// logs err.Error(), doesn't log the values of redacted fields. It must be chained
+// right after grpc_zap.UnaryServerInterceptor.
+func redactErrorsUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, errors.Redacted(err)
+	}
+}
@@ -6,8 +6,13 @@ import (
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_zap "github.com/grpc-ecosystem/go-grpc-middleware/logging/zap"
 	"github.com/jwenz723/errhandling/pb"
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/errors/report"
+	"github.com/jwenz723/errhandling/pkg/errors/trace"
+	"github.com/jwenz723/errhandling/pkg/requestid"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"net"
 	"os"
 	"time"
@@ -19,10 +24,22 @@ func main() {
 
 	fs := flag.NewFlagSet(svcName, flag.ExitOnError)
 	grpcAddr := fs.String("grpc-addr", ":8082", "gRPC listen address")
+	reportFile := fs.String("report-file", "", "JSONL file server errors are reported to")
+	reportURL := fs.String("report-url", "", "HTTP endpoint server errors are reported to")
+	locale := fs.String("locale", "es", "locale the client asks error messages in")
+	printSpans := fs.Bool("print-spans", false, "print the spans recorded in memory before exiting")
+	debugInfo := fs.Bool("status-debug-info", false, "send stacks to clients in status details, for trusted deployments only")
 	fs.Parse(os.Args[1:])
+	errors.StatusDebugInfo = *debugInfo
 
 	logger, _ := zap.NewProduction()
 
+	reporters, closeReporters, err := report.Open(*reportFile, *reportURL)
+	if err != nil {
+		logger.Error("failed to open report file", zap.Error(err))
+	}
+	defer closeReporters()
+
 	// Setup the server
 	logger.Info("starting grpcSvc listener",
 		zap.String("addr", *grpcAddr))
@@ -31,10 +48,19 @@ func main() {
 		logger.Error("failed to start grpcSvc listener", zap.Error(err))
 	}
 
+	tracer := trace.NewInMemory()
+
 	grpcSvc := grpcServer{}
 	grpcServer := grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+			errors.LocaleUnaryServerInterceptor(),
+			requestid.UnaryServerInterceptor(),
+			trace.UnaryServerInterceptor(tracer),
 			grpc_zap.UnaryServerInterceptor(logger),
+			redactErrorsUnaryServerInterceptor(),
+			requestIDUnaryServerInterceptor(),
+			errorFieldsUnaryServerInterceptor(),
+			report.UnaryServerInterceptor(reporters),
 		)),
 	)
 	pb.RegisterOrdersServer(grpcServer, &grpcSvc)
@@ -43,13 +69,34 @@ func main() {
 	}()
 
 	// Do a client request to the server
-	conn, err := grpc.Dial(*grpcAddr, grpc.WithInsecure())
+	conn, err := grpc.Dial(*grpcAddr, grpc.WithInsecure(), grpc.WithUnaryInterceptor(grpc_middleware.ChainUnaryClient(
+		requestid.UnaryClientInterceptor(),
+		trace.UnaryClientInterceptor(tracer),
+	)))
 	if err != nil {
 		panic(err)
 	}
 	s := pb.NewOrdersClient(conn)
-	_, _ = s.NewOrder(context.TODO(), &pb.NewOrderRequest{CustomerID: "123"})
+	ctx := requestid.NewContext(context.TODO(), requestid.New())
+	ctx = metadata.AppendToOutgoingContext(ctx, errors.LocaleKey, *locale)
+	if _, err := s.NewOrder(ctx, &pb.NewOrderRequest{CustomerID: "123"}); err != nil {
+		const op = errors.Op("client.NewOrder")
+		if remote, ok := errors.FromError(err); ok {
+			err = remote
+		}
+		e := errors.E(op, err)
+		fields := append(errorFields(e), zap.String("Error.Message", string(errors.GrpcMsg(e))))
+		if errors.IsKind(e, errors.KindBadRequest) {
+			logger.Info("NewOrder rejected", fields...)
+		} else {
+			logger.Error("NewOrder failed", fields...)
+		}
+	}
 
 	lis.Close()
 	time.Sleep(1 * time.Second)
+
+	if *printSpans {
+		_ = tracer.Dump(os.Stdout)
+	}
 }
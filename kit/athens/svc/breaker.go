@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+	"golang.org/x/xerrors"
+)
+
+// ErrBreakerOpen is returned, wrapped in an Error of
+// KindUnavailable, when a circuit breaker rejects a call.
+var ErrBreakerOpen = xerrors.New("circuit breaker is open")
+
+// BreakerKinds are the Kinds of server side failures
+// that count toward tripping a circuit breaker. Client
+// errors such as BadRequest or NotFound never do.
+var BreakerKinds = map[int]bool{
+	errors2.KindUnexpected:       true,
+	errors2.KindUnavailable:      true,
+	errors2.KindDeadlineExceeded: true,
+}
+
+// breaker is a consecutive failure circuit breaker.
+// It opens after a number of server side failures in
+// a row, rejects calls for cooldown, and then lets a
+// single call through to decide whether to close.
+type breaker struct {
+	failures int
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	count     int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow reports whether a call may be made.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.count < b.failures {
+		return true
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// outcome is how a call affects a breaker.
+type outcome int
+
+const (
+	// callSucceeded closes the breaker, client
+	// errors count as success since the remote
+	// answered them.
+	callSucceeded outcome = iota
+	// callFailed counts toward tripping the breaker.
+	callFailed
+	// callAbandoned is a call the caller gave up
+	// on, which says nothing about the remote.
+	callAbandoned
+)
+
+// record updates the breaker with the outcome of a call.
+// An abandoned call leaves the count alone, so that a
+// probe the caller canceled can simply be made again.
+func (b *breaker) record(o outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch o {
+	case callAbandoned:
+		return
+	case callSucceeded:
+		b.count = 0
+		return
+	}
+	b.count++
+	if b.count >= b.failures {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
+
+// BreakerMiddleware returns a client endpoint middleware
+// that stops calling next after the given number of server
+// side failures in a row, as classified by BreakerKinds, from
+// either the transport or endpoint.Failer. While open it
+// returns an Error with op, KindUnavailable and
+// ErrBreakerOpen, so that callers can tell a rejection
+// apart from a remote failure with errors.Is. After
+// cooldown a single call is let through to test the
+// remote, closing the breaker again if it succeeds.
+func BreakerMiddleware(op errors2.Op, failures int, cooldown time.Duration) endpoint.Middleware {
+	b := &breaker{failures: failures, cooldown: cooldown}
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			if !b.allow() {
+				return nil, errors2.E(op, ErrBreakerOpen, errors2.KindUnavailable)
+			}
+			response, err := next(ctx, request)
+			b.record(outcomeOf(ctx, failure(response, err)))
+			return response, err
+		}
+	}
+}
+
+// outcomeOf classifies a call that returned err. Calls
+// the caller gave up on are abandoned whatever err is.
+func outcomeOf(ctx context.Context, err error) outcome {
+	if ctx.Err() != nil {
+		return callAbandoned
+	}
+	if err == nil {
+		return callSucceeded
+	}
+	if remote, ok := errors2.FromError(err); ok {
+		err = remote
+	}
+	if BreakerKinds[errors2.Kind(err)] {
+		return callFailed
+	}
+	return callSucceeded
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func TestBreakerOpens(t *testing.T) {
+	calls := 0
+	ep := BreakerMiddleware("client.Test", 2, time.Hour)(func(context.Context, interface{}) (interface{}, error) {
+		calls++
+		return nil, errors2.E("remote", "down", errors2.KindUnavailable)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, _ = ep(ctx, nil)
+	}
+	if calls != 2 {
+		t.Fatalf("next was called %d times, want 2", calls)
+	}
+	_, err := ep(ctx, nil)
+	if !errors2.Is(err, ErrBreakerOpen) || !errors2.IsKind(err, errors2.KindUnavailable) {
+		t.Fatalf("err = %v, want ErrBreakerOpen of KindUnavailable", err)
+	}
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	calls := 0
+	ep := BreakerMiddleware("client.Test", 1, time.Hour)(func(context.Context, interface{}) (interface{}, error) {
+		calls++
+		return nil, errors2.E("remote", "bad", errors2.KindBadRequest)
+	})
+	for i := 0; i < 3; i++ {
+		_, _ = ep(context.Background(), nil)
+	}
+	if calls != 3 {
+		t.Fatalf("next was called %d times, want 3", calls)
+	}
+}
+
+func TestBreakerCanceledCalls(t *testing.T) {
+	b := &breaker{failures: 2, cooldown: time.Hour}
+	b.record(callFailed)
+	b.record(callAbandoned)
+	b.record(callFailed)
+	if b.allow() {
+		t.Fatal("an abandoned call reset the consecutive failures")
+	}
+
+	// a canceled probe keeps the breaker open
+	// but lets the next call probe again.
+	b.openUntil = time.Now()
+	if !b.allow() {
+		t.Fatal("no probe was let through after cooldown")
+	}
+	b.record(callAbandoned)
+	if b.count < b.failures {
+		t.Fatal("a canceled probe closed the breaker")
+	}
+	if !b.allow() {
+		t.Fatal("no new probe was let through after a canceled one")
+	}
+}
+
+func TestOutcomeOfCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if o := outcomeOf(ctx, nil); o != callAbandoned {
+		t.Errorf("outcome of a canceled call = %v, want callAbandoned", o)
+	}
+	if o := outcomeOf(context.Background(), errors2.E("remote", "down", errors2.KindUnavailable)); o != callFailed {
+		t.Errorf("outcome of an unavailable remote = %v, want callFailed", o)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
-	"github.com/go-kit/kit/log/level"
-	errors2 "github.com/jwenz723/errhandling/kit/athens/errors"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/errors/report"
+	"github.com/jwenz723/errhandling/pkg/errors/trace"
+	"github.com/opentracing/opentracing-go"
+	"time"
 )
 
 // Set collects all of the endpoints that compose an add service. It's meant to
@@ -18,20 +21,35 @@ type Set struct {
 
 // New returns a Set that wraps the provided server, and wires in all of the
 // expected endpoint middlewares via the various parameters.
-func NewSet(svc OrderService, logger log.Logger) Set {
+func NewSet(svc OrderService, logger log.Logger, reporter report.Reporter, tracer opentracing.Tracer) Set {
 	var newOrderEndpoint endpoint.Endpoint
 	{
 		methodLogger := log.With(logger, "method", "NewOrder")
-		infoLogger := level.Info(methodLogger)
-		errorLogger := level.Error(methodLogger)
 		newOrderEndpoint = MakeNewOrderEndpoint(svc)
-		newOrderEndpoint = LoggingMiddleware(infoLogger, errorLogger)(newOrderEndpoint)
+		newOrderEndpoint = LoggingMiddleware(methodLogger, reporter)(newOrderEndpoint)
+		newOrderEndpoint = trace.TraceServer(tracer, "NewOrder")(newOrderEndpoint)
 	}
 	return Set{
 		NewOrderEndpoint: newOrderEndpoint,
 	}
 }
 
+// clientMiddleware returns the middlewares shared by every
+// client endpoint. The breaker wraps the retries so that a
+// retried call counts as a single failure, and each attempt
+// gets its own span.
+func clientMiddleware(op errors2.Op, tracer opentracing.Tracer) endpoint.Middleware {
+	return endpoint.Chain(
+		BreakerMiddleware(op, 5, 10*time.Second),
+		RetryMiddleware(op, Backoff{
+			Attempts: 4,
+			Base:     50 * time.Millisecond,
+			Max:      time.Second,
+		}),
+		trace.TraceClient(tracer, string(op)),
+	)
+}
+
 // Sum implements the service interface, so Set may be used as a service.
 // This is primarily useful in the context of a client library.
 func (s Set) NewOrder(ctx context.Context, customerID string) (string, error) {
@@ -48,8 +66,14 @@ func MakeNewOrderEndpoint(s OrderService) endpoint.Endpoint {
 	const op = errors2.Op("endpoint.NewOrder")
 	return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 		req := request.(NewOrderRequest)
-		orderID, err := NewOrder(ctx, req.CustomerID)
-		return NewOrderResponse{OrderID: orderID, Err: errors2.E(op, err)}, nil
+		// the edge of the service: errors built with
+		// EC further down pick up the customer ID.
+		ctx = errors2.WithFields(ctx, errors2.CustomerIDField.V(req.CustomerID))
+		orderID, err := s.NewOrder(ctx, req.CustomerID)
+		if err != nil {
+			err = errors2.EC(ctx, op, err)
+		}
+		return NewOrderResponse{OrderID: orderID, Err: err}, nil
 	}
 }
 
@@ -59,13 +83,15 @@ var (
 )
 
 type NewOrderRequest struct {
-	CustomerID string
+	CustomerID string `json:"customer_id"`
 }
 
 // AppendKeyvals implements eplogger.AppendKeyvalser
 func (r NewOrderRequest) AppendKeyvals(keyvals []interface{}) []interface{} {
-	return append(keyvals,
-		"NewOrderRequest.CustomerID", r.CustomerID)
+	if v, ok := errors2.Redact(errors2.CustomerIDField, r.CustomerID); ok {
+		keyvals = append(keyvals, "NewOrderRequest.CustomerID", v)
+	}
+	return keyvals
 }
 
 // SumResponse collects the response values for the Sum method.
@@ -83,14 +109,22 @@ func (r NewOrderResponse) AppendKeyvals(keyvals []interface{}) []interface{} {
 			"NewOrderResponse.Err", fmt.Sprintf("%+v", r.Err))
 	}
 
-	return append(keyvals,
+	keyvals = append(keyvals,
 		"NewOrderResponse.OrderID", r.OrderID,
 		"NewOrderResponse.Err.Op", e.Op,
 		"NewOrderResponse.Err.Kind", errors2.KindText(e),
-		"NewOrderResponse.Err.CustomerID", errors2.CustomerID(e),
-		"NewOrderResponse.Err.OrderID", errors2.OrderID(e),
 		"NewOrderResponse.Err.Ops", errors2.OpsText(e))
+	for _, kv := range errors2.RedactedFields(e) {
+		keyvals = append(keyvals, "NewOrderResponse.Err."+kv.Field.String(), kv.Value)
+	}
+	return keyvals
 }
 
 // Failed implements endpoint.Failer.
 func (r NewOrderResponse) Failed() error { return r.Err }
+
+// withErr implements errSetter.
+func (r NewOrderResponse) withErr(err error) interface{} {
+	r.Err = err
+	return r
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/errors/report"
+	"github.com/jwenz723/errhandling/pkg/requestid"
 	"time"
 )
 
@@ -31,8 +35,11 @@ type AppendKeyvalser interface {
 }
 
 const (
-	tookKey     = "took"
-	transErrKey = "transport_error"
+	tookKey        = "took"
+	transErrKey    = "transport_error"
+	fingerprintKey = "fingerprint"
+	reportErrKey   = "report_error"
+	requestIDKey   = "request_id"
 )
 
 // LoggingMiddleware returns an endpoint middleware that logs the
@@ -40,24 +47,66 @@ const (
 // keyvals specific to the request and response object if they implement
 // the LoggingKeyvalser interface.
 //
-// The level specified as defaultLevel will be used when the resulting error
-// is nil otherwise level.Error will be used.
-func LoggingMiddleware(logger, errLogger log.Logger) endpoint.Middleware {
+// level.Info will be used when there is no resulting error, otherwise the
+// level is resolved by errors.Severity from either the transport error or
+// the error returned by endpoint.Failer, and the error's fingerprint is logged.
+//
+// If reporter is not nil every error is also sent to it along with the
+// keyvals of the request. The request ID of ctx, if any, is logged too.
+func LoggingMiddleware(logger log.Logger, reporter report.Reporter) endpoint.Middleware {
 	return func(next endpoint.Endpoint) endpoint.Endpoint {
 		return func(ctx context.Context, request interface{}) (response interface{}, err error) {
 			defer func(begin time.Time) {
 				kvs := makeKeyvals(request, response, time.Since(begin), err)
-				if err != nil {
-					errLogger.Log(kvs...)
-				} else {
-					logger.Log(kvs...)
+				if id, ok := requestid.FromContext(ctx); ok {
+					kvs = append(kvs, requestIDKey, id)
+				}
+				ferr := failure(response, err)
+				if ferr != nil {
+					kvs = append(kvs, fingerprintKey, errors2.Fingerprint(ferr))
 				}
+				if ferr != nil && reporter != nil {
+					if rerr := report.Send(ctx, reporter, ferr, requestMetadata(request)); rerr != nil {
+						kvs = append(kvs, reportErrKey, rerr)
+					}
+				}
+				log.WithPrefix(logger, level.Key(), severity(ferr)).Log(kvs...)
 			}(time.Now())
 			return next(ctx, request)
 		}
 	}
 }
 
+// failure returns the transport error if there is one, otherwise
+// the error returned by resp if it implements endpoint.Failer.
+func failure(resp interface{}, err error) error {
+	if f, ok := resp.(endpoint.Failer); ok && err == nil {
+		return f.Failed()
+	}
+	return err
+}
+
+// requestMetadata returns the keyvals of req as report metadata.
+func requestMetadata(req interface{}) map[string]string {
+	md := map[string]string{}
+	if l, ok := req.(AppendKeyvalser); ok {
+		kvs := l.AppendKeyvals(nil)
+		for i := 0; i+1 < len(kvs); i += 2 {
+			md[fmt.Sprint(kvs[i])] = fmt.Sprint(kvs[i+1])
+		}
+	}
+	return md
+}
+
+// severity returns the level an invocation that resulted in err
+// should be logged at.
+func severity(err error) level.Value {
+	if err == nil {
+		return level.InfoValue()
+	}
+	return errors2.Severity(err)
+}
+
 // makeKeyvals will place the received parameters into an []interface{} to be
 // returned in the order:
 // 	1. err
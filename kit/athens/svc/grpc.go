@@ -2,35 +2,99 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/go-kit/kit/log"
+	kitot "github.com/go-kit/kit/tracing/opentracing"
 	"github.com/go-kit/kit/transport"
 	grpctransport "github.com/go-kit/kit/transport/grpc"
-	errors2 "github.com/jwenz723/errhandling/kit/athens/errors"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/jwenz723/errhandling/pb"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/requestid"
+	"github.com/opentracing/opentracing-go"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"time"
 )
 
 type grpcServer struct {
 	newOrder grpctransport.Handler
 }
 
+// GRPCServerOption configures NewGRPCServer.
+type GRPCServerOption func(*grpcServerConfig)
+
+type grpcServerConfig struct {
+	statusErrors bool
+}
+
+// StatusErrors makes the server return the endpoint.Failer
+// error of a response as a gRPC status error, with its code
+// and details from GRPCStatus, instead of in the reply. This
+// lets gRPC aware tooling see failed calls as failed.
+func StatusErrors() GRPCServerOption {
+	return func(c *grpcServerConfig) {
+		c.statusErrors = true
+	}
+}
+
 // NewGRPCServer makes a set of endpoints available as a gRPC AddServer.
-func NewGRPCServer(endpoints Set, logger log.Logger) pb.OrdersServer {
+func NewGRPCServer(endpoints Set, tracer opentracing.Tracer, logger log.Logger, opts ...GRPCServerOption) pb.OrdersServer {
+	var cfg grpcServerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	options := []grpctransport.ServerOption{
-		grpctransport.ServerErrorHandler(transport.NewLogErrorHandler(logger)),
+		grpctransport.ServerErrorHandler(failedErrorHandler{transport.NewLogErrorHandler(logger)}),
+		grpctransport.ServerBefore(requestid.GRPCServerBefore),
+		grpctransport.ServerBefore(kitot.GRPCToContext(tracer, "NewOrder", logger)),
 	}
 
+	encode := encodeGRPCNewOrderResponse
+	if cfg.statusErrors {
+		encode = encodeGRPCNewOrderStatus
+	}
 	return &grpcServer{
 		newOrder: grpctransport.NewServer(
 			endpoints.NewOrderEndpoint,
 			decodeGRPCNewOrderRequest,
-			encodeGRPCNewOrderResponse,
+			encode,
 			options...,
 		),
 	}
 }
 
+// failedError is an endpoint.Failer error returned as a
+// status error, with the status localized for the request.
+type failedError struct {
+	err errors2.Error
+	st  *status.Status
+}
+
+func (f failedError) Error() string { return f.err.Error() }
+
+func (f failedError) GRPCStatus() *status.Status { return f.st }
+
+// failedErrorHandler passes transport errors to the wrapped
+// ErrorHandler, skipping failedErrors since the logging
+// middleware already logged them. Errors are wrapped with
+// errors.Redacted since loggers log their Error text.
+type failedErrorHandler struct {
+	transport.ErrorHandler
+}
+
+func (h failedErrorHandler) Handle(ctx context.Context, err error) {
+	if _, ok := err.(failedError); ok {
+		return
+	}
+	h.ErrorHandler.Handle(ctx, errors2.Redacted(err))
+}
+
 func (s *grpcServer) NewOrder(ctx context.Context, req *pb.NewOrderRequest) (*pb.NewOrderReply, error) {
 	_, rep, err := s.newOrder.ServeGRPC(ctx, req)
 	if err != nil {
@@ -49,23 +113,158 @@ func decodeGRPCNewOrderRequest(_ context.Context, grpcReq interface{}) (interfac
 // encodeGRPCNewOrderResponse is a transport/grpc.EncodeResponseFunc that converts a
 // user-domain sum response to a gRPC sum reply. Primarily useful in a server.
 func encodeGRPCNewOrderResponse(_ context.Context, response interface{}) (interface{}, error) {
-	const op = errors2.Op("grpc.NewOrder")
 	resp := response.(NewOrderResponse)
-	return &pb.NewOrderReply{OrderID: resp.OrderID, Err: errors2.E(op, resp.Err).Error()}, nil
+	return &pb.NewOrderReply{OrderID: resp.OrderID, Err: err2pb(errors2.Op("grpc.NewOrder"), resp.Err)}, nil
+}
+
+// encodeGRPCNewOrderStatus is like encodeGRPCNewOrderResponse but returns
+// the error of a failed response as a status error, its message localized
+// to the accept-language of the request. Used by StatusErrors.
+func encodeGRPCNewOrderStatus(ctx context.Context, response interface{}) (interface{}, error) {
+	resp := response.(NewOrderResponse)
+	if resp.Err != nil {
+		err := errors2.E(errors2.Op("grpc.NewOrder"), resp.Err)
+		return nil, failedError{err, errors2.LocalizedStatus(err, errors2.Locale(ctx))}
+	}
+	return encodeGRPCNewOrderResponse(ctx, response)
+}
+
+// err2pb and pb2err translate athens errors to and from pb.Error, which is
+// the type we use in our IDLs to represent errors. The Kind, ErrorCode, Op
+// chain, severity, retryability, GM, explicit gRPC code, field violations
+// and redacted fields all survive the trip, though field values come back
+// as strings. The children of a MultiError are sent as pb.Errors of their
+// own, so that each keeps its Kind and Ops. There is special casing to
+// treat nil pb.Errors as nil errors.
+
+func err2pb(op errors2.Op, err error) *pb.Error {
+	if err == nil {
+		return nil
+	}
+	return toPB(errors2.E(op, err))
 }
 
-// These annoying helper functions are required to translate Go error types to
-// and from strings, which is the type we use in our IDLs to represent errors.
-// There is special casing to treat empty strings as nil errors.
+// toPB encodes err. The Ops of err's chain end at the
+// first MultiError in it, whose children are encoded
+// in turn rather than flattened into err's Ops.
+func toPB(err error) *pb.Error {
+	e := &pb.Error{
+		Code:         errors2.CodeOf(err).String(),
+		Kind:         int32(errors2.Kind(err)),
+		Message:      errors2.RedactText(err, err.Error()),
+		Severity:     errors2.Severity(err).String(),
+		Retryable:    errors2.Retryable(err),
+		RetryAfterMs: int64(errors2.RetryAfter(err) / time.Millisecond),
+	}
+	var children []error
+	errors2.Walk(err, func(err error) bool {
+		var op errors2.Op
+		switch x := err.(type) {
+		case errors2.Error:
+			op = x.Op
+		case *errors2.Error:
+			op = x.Op
+		case errors2.MultiError:
+			op, children = x.Op, x.Errors
+		case *errors2.MultiError:
+			op, children = x.Op, x.Errors
+		}
+		if op != "" {
+			e.Ops = append(e.Ops, string(op))
+		}
+		return true
+	})
+	for _, child := range children {
+		e.Errors = append(e.Errors, toPB(child))
+	}
+	for _, kv := range errors2.RedactedFields(err) {
+		if e.Fields == nil {
+			e.Fields = map[string]string{}
+		}
+		e.Fields[kv.Field.String()] = fmt.Sprint(kv.Value)
+	}
+	if gm := errors2.GrpcMsg(err); gm != "" {
+		e.GrpcMsg = errors2.RedactText(err, string(gm))
+	}
+	if c := errors2.GrpcCode(err); c != nil {
+		e.GrpcCode = &wrappers.Int32Value{Value: int32(*c)}
+	}
+	for _, fv := range errors2.BadRequest(err).GetFieldViolations() {
+		e.BadRequest = append(e.BadRequest, &pb.FieldViolation{Field: fv.Field, Description: fv.Description})
+	}
+	return e
+}
 
-func str2err(op errors2.Op, s string) error {
-	if s == "" {
+// pb2err rebuilds the error encoded in e. The
+// remote Op chain already ends with the op that
+// err2pb added, op is only used if e has no Ops.
+func pb2err(op errors2.Op, e *pb.Error) error {
+	if e == nil {
 		return nil
 	}
-	return errors2.E(op, s)
+	ops := e.Ops
+	if len(ops) == 0 {
+		ops = []string{string(op)}
+	}
+	return fromPB(e, ops)
+}
+
+// fromPB rebuilds the error encoded in e, with an
+// Error for each of ops and a MultiError of its
+// children if it has any.
+func fromPB(e *pb.Error, ops []string) errors2.Error {
+	var msg interface{} = e.Message
+	if len(e.Errors) > 0 {
+		children := make([]error, len(e.Errors))
+		for i, child := range e.Errors {
+			children[i] = fromPB(child, child.Ops)
+		}
+		msg = &errors2.MultiError{Errors: children}
+	}
+	args := []interface{}{msg, int(e.Kind), errors2.Retry(e.Retryable)}
+	if e.Code != "" {
+		args = append(args, errors2.ErrorCode(e.Code))
+	}
+	if v := errors2.ParseSeverity(e.Severity); v != nil {
+		args = append(args, v)
+	}
+	if e.RetryAfterMs > 0 {
+		args = append(args, time.Duration(e.RetryAfterMs)*time.Millisecond)
+	}
+	if e.GrpcMsg != "" {
+		args = append(args, errors2.GM(e.GrpcMsg))
+	}
+	if e.GrpcCode != nil {
+		args = append(args, codes.Code(e.GrpcCode.Value))
+	}
+	for _, fv := range e.BadRequest {
+		args = append(args, &errdetails.BadRequest_FieldViolation{Field: fv.Field, Description: fv.Description})
+	}
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, errors2.Field(k).V(e.Fields[k]))
+	}
+
+	// rebuild the remote Op chain, innermost first.
+	if len(ops) == 0 {
+		// E derives an Op when given none, a
+		// child that had none mustn't get one.
+		err := errors2.E("", args...)
+		err.Op = ""
+		return err
+	}
+	err := errors2.E(errors2.Op(ops[len(ops)-1]), args...)
+	for i := len(ops) - 2; i >= 0; i-- {
+		err = errors2.E(errors2.Op(ops[i]), err)
+	}
+	return err
 }
 
-func NewGRPCClient(conn *grpc.ClientConn, logger log.Logger) OrderService {
+func NewGRPCClient(conn *grpc.ClientConn, tracer opentracing.Tracer, logger log.Logger) OrderService {
 	pbServiceName := "pb.Orders"
 
 	var newOrderEndpoint endpoint.Endpoint
@@ -78,7 +277,11 @@ func NewGRPCClient(conn *grpc.ClientConn, logger log.Logger) OrderService {
 			encodeGRPCNewOrderRequest,
 			decodeGRPCNewOrderResponse,
 			pb.NewOrderReply{},
+			grpctransport.ClientBefore(requestid.GRPCClientBefore),
+			grpctransport.ClientBefore(kitot.ContextToGRPC(tracer, logger)),
 		).Endpoint()
+		newOrderEndpoint = decodeGRPCNewOrderStatus(newOrderEndpoint)
+		newOrderEndpoint = clientMiddleware(errors2.Op("client.NewOrder"), tracer)(newOrderEndpoint)
 	}
 
 	return Set{
@@ -93,5 +296,18 @@ func encodeGRPCNewOrderRequest(_ context.Context, request interface{}) (interfac
 
 func decodeGRPCNewOrderResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
 	reply := grpcReply.(*pb.NewOrderReply)
-	return NewOrderResponse{OrderID: reply.OrderID, Err: str2err(errors2.Op("grpc.NewOrder"), reply.Err)}, nil
+	return NewOrderResponse{OrderID: reply.OrderID, Err: pb2err(errors2.Op("grpc.NewOrder"), reply.Err)}, nil
+}
+
+// decodeGRPCNewOrderStatus returns a client endpoint that decodes the status
+// errors of a server using StatusErrors back into NewOrderResponse.Err. Other
+// status errors, such as an unreachable server, stay transport errors.
+func decodeGRPCNewOrderStatus(next endpoint.Endpoint) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		response, err := next(ctx, request)
+		if st, ok := status.FromError(err); ok && err != nil && errors2.HasErrorInfo(st) {
+			return NewOrderResponse{Err: errors2.E(errors2.Op("grpc.NewOrder"), errors2.FromStatus(st))}, nil
+		}
+		return response, err
+	}
 }
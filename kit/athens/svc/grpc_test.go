@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/jwenz723/errhandling/pb"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/ordercodes"
+	"golang.org/x/xerrors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestPBErrorRoundTrip(t *testing.T) {
+	err := errors2.E("service.NewOrder",
+		errors2.E("db.Insert", "customer 42 has too many orders",
+			errors2.KindRateLimit,
+			codes.Aborted,
+			errors2.GM("slow down"),
+			errors2.C("42"),
+			errors2.O("o-1"),
+			&errdetails.BadRequest_FieldViolation{Field: "quantity", Description: "too large"},
+			2*time.Second,
+		),
+	)
+
+	remote := viaPB(t, "endpoint.NewOrder", err)
+
+	if got, want := errors2.Kind(remote), errors2.KindRateLimit; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	if got, want := errors2.Code(remote), codes.Aborted; got != want {
+		t.Errorf("Code = %v, want %v", got, want)
+	}
+	if got, want := errors2.GrpcMsg(remote), errors2.GM("slow down"); got != want {
+		t.Errorf("GrpcMsg = %q, want %q", got, want)
+	}
+	wantOps := []errors2.Op{"endpoint.NewOrder", "service.NewOrder", "db.Insert"}
+	if got := errors2.Ops(remote); !reflect.DeepEqual(got, wantOps) {
+		t.Errorf("Ops = %v, want %v", got, wantOps)
+	}
+	if got, want := errors2.OrderID(remote), errors2.O("o-1"); got != want {
+		t.Errorf("OrderID = %q, want %q", got, want)
+	}
+	if got := errors2.CustomerID(remote); got == "42" {
+		t.Error("customer ID crossed the wire unredacted")
+	}
+	if got := remote.Error(); got == "customer 42 has too many orders" {
+		t.Error("message crossed the wire unredacted")
+	}
+	if got, want := errors2.RetryAfter(remote), 2*time.Second; got != want {
+		t.Errorf("RetryAfter = %v, want %v", got, want)
+	}
+	if !errors2.Retryable(remote) {
+		t.Error("Retryable = false, want true")
+	}
+	if got, want := errors2.Severity(remote), errors2.Severity(err); got.String() != want.String() {
+		t.Errorf("Severity = %v, want %v", got, want)
+	}
+	br := errors2.BadRequest(remote)
+	if br == nil || len(br.FieldViolations) != 1 || br.FieldViolations[0].Field != "quantity" {
+		t.Errorf("BadRequest = %v, want the quantity violation", br)
+	}
+}
+
+// viaPB sends err through protobuf
+// as err2pb and pb2err do.
+func viaPB(t *testing.T, op errors2.Op, err error) error {
+	t.Helper()
+	b, merr := proto.Marshal(&pb.NewOrderReply{Err: err2pb(op, err)})
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	var reply pb.NewOrderReply
+	if merr := proto.Unmarshal(b, &reply); merr != nil {
+		t.Fatal(merr)
+	}
+	return pb2err(op, reply.Err)
+}
+
+func TestPBErrorMulti(t *testing.T) {
+	err := errors2.E("service.NewOrder", errors2.Multi("order.Validate",
+		errors2.E("line1", "quantity must be positive", errors2.KindBadRequest,
+			&errdetails.BadRequest_FieldViolation{Field: "lines[0].quantity", Description: "must be positive"}),
+		errors2.E("line2", "sku not found", errors2.KindNotFound),
+		xerrors.New("foreign"),
+	))
+	remote := viaPB(t, "grpc.NewOrder", err)
+
+	wantOps := []errors2.Op{"grpc.NewOrder", "service.NewOrder", "order.Validate", "line1", "line2"}
+	if got := errors2.Ops(remote); !reflect.DeepEqual(got, wantOps) {
+		t.Errorf("Ops = %v, want %v", got, wantOps)
+	}
+	if got, want := errors2.Kind(remote), errors2.Kind(err); got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	children := errors2.Errors(remote)
+	if len(children) != 3 {
+		t.Fatalf("%d children, want 3", len(children))
+	}
+	wantKinds := []int{errors2.KindBadRequest, errors2.KindNotFound, errors2.KindUnexpected}
+	for i, child := range children {
+		if got := errors2.Kind(child); got != wantKinds[i] {
+			t.Errorf("child %d Kind = %d, want %d", i, got, wantKinds[i])
+		}
+	}
+	if ops := errors2.Ops(children[2]); len(ops) != 0 {
+		t.Errorf("foreign child got Ops %v", ops)
+	}
+	if br := errors2.BadRequest(children[0]); br == nil || len(br.FieldViolations) != 1 {
+		t.Errorf("BadRequest of line1 = %v, want its violation", br)
+	}
+}
+
+func TestPBErrorNil(t *testing.T) {
+	if e := err2pb("op", nil); e != nil {
+		t.Errorf("err2pb(nil) = %v, want nil", e)
+	}
+	if err := pb2err("op", nil); err != nil {
+		t.Errorf("pb2err(nil) = %v, want nil", err)
+	}
+}
+
+func TestStatusErrorsRoundTrip(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(errors2.LocaleKey, "es-MX, en;q=0.5"))
+	resp := NewOrderResponse{Err: errors2.E("service.NewOrder", ErrEmpty, ordercodes.CustomerRequired)}
+	_, err := encodeGRPCNewOrderStatus(ctx, resp)
+	if err == nil {
+		t.Fatal("a failed response was encoded without an error")
+	}
+
+	// send the status through protobuf as the transport does.
+	b, merr := proto.Marshal(status.Convert(err).Proto())
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	var p spb.Status
+	if merr := proto.Unmarshal(b, &p); merr != nil {
+		t.Fatal(merr)
+	}
+	st := status.FromProto(&p)
+	if got, want := st.Message(), "se requiere un ID de cliente"; got != want {
+		t.Errorf("status message = %q, want %q", got, want)
+	}
+
+	client := decodeGRPCNewOrderStatus(func(context.Context, interface{}) (interface{}, error) {
+		return nil, st.Err()
+	})
+	response, err := client(context.Background(), NewOrderRequest{})
+	if err != nil {
+		t.Fatalf("the status was returned as a transport error: %v", err)
+	}
+	remote := response.(NewOrderResponse).Err
+	if got, want := errors2.Kind(remote), errors2.KindBadRequest; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	if got, want := errors2.CodeOf(remote), ordercodes.CustomerRequired; got != want {
+		t.Errorf("CodeOf = %q, want %q", got, want)
+	}
+	if got, want := errors2.Code(remote), codes.InvalidArgument; got != want {
+		t.Errorf("Code = %v, want %v", got, want)
+	}
+}
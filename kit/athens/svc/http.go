@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/log"
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/requestid"
+	"github.com/opentracing/opentracing-go"
+)
+
+// NewHTTPHandler makes a set of endpoints available as JSON over HTTP.
+func NewHTTPHandler(endpoints Set, tracer opentracing.Tracer, logger log.Logger) http.Handler {
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorEncoder(errorEncoder),
+		httptransport.ServerErrorHandler(failedErrorHandler{transport.NewLogErrorHandler(logger)}),
+		httptransport.ServerBefore(requestid.HTTPServerBefore),
+		httptransport.ServerBefore(kitot.HTTPToContext(tracer, "NewOrder", logger)),
+	}
+
+	m := http.NewServeMux()
+	m.Handle("/neworder", httptransport.NewServer(
+		endpoints.NewOrderEndpoint,
+		decodeHTTPNewOrderRequest,
+		encodeHTTPGenericResponse,
+		options...,
+	))
+	return m
+}
+
+// errorEncoder writes err as an RFC 7807 errors.Problem,
+// with the HTTP status taken from its Kind.
+func errorEncoder(_ context.Context, err error, w http.ResponseWriter) {
+	errors2.WriteProblem(w, errors2.E(errors2.Op("http.NewOrder"), err))
+}
+
+// decodeHTTPNewOrderRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded NewOrder request from the HTTP request body. Primarily useful in a
+// server.
+func decodeHTTPNewOrderRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req NewOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors2.E(errors2.Op("http.decodeNewOrderRequest"), err, errors2.KindBadRequest)
+	}
+	return req, nil
+}
+
+// encodeHTTPGenericResponse is a transport/http.EncodeResponseFunc that encodes
+// the response as JSON to the response writer. Responses that implement
+// endpoint.Failer and failed are written with errorEncoder instead.
+func encodeHTTPGenericResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if f, ok := response.(endpoint.Failer); ok && f.Failed() != nil {
+		errorEncoder(ctx, f.Failed(), w)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// NewHTTPClient returns an OrderService backed by an HTTP server living at the
+// remote instance. We expect instance to come from a service discovery system,
+// so likely of the form "host:port".
+func NewHTTPClient(instance string, tracer opentracing.Tracer, logger log.Logger) (OrderService, error) {
+	if !strings.HasPrefix(instance, "http") {
+		instance = "http://" + instance
+	}
+	u, err := url.Parse(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	var newOrderEndpoint endpoint.Endpoint
+	{
+		newOrderEndpoint = httptransport.NewClient(
+			http.MethodPost,
+			copyURL(u, "/neworder"),
+			encodeHTTPGenericRequest,
+			decodeHTTPNewOrderResponse,
+			httptransport.ClientBefore(requestid.HTTPClientBefore),
+			httptransport.ClientBefore(kitot.ContextToHTTP(tracer, logger)),
+		).Endpoint()
+		newOrderEndpoint = clientMiddleware(errors2.Op("client.NewOrder"), tracer)(newOrderEndpoint)
+	}
+
+	return Set{
+		NewOrderEndpoint: newOrderEndpoint,
+	}, nil
+}
+
+// encodeHTTPGenericRequest is a transport/http.EncodeRequestFunc that
+// JSON-encodes any request to the request body. Primarily useful in a client.
+func encodeHTTPGenericRequest(_ context.Context, r *http.Request, request interface{}) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(request); err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	r.Body = ioutil.NopCloser(&buf)
+	return nil
+}
+
+// decodeHTTPNewOrderResponse is a transport/http.DecodeResponseFunc that decodes
+// a JSON-encoded NewOrder response from the HTTP response body. An error
+// response is rebuilt from its problem details into the athens error
+// the server encoded.
+func decodeHTTPNewOrderResponse(_ context.Context, r *http.Response) (interface{}, error) {
+	if remote, ok := errors2.FromHTTPResponse(r); ok {
+		return NewOrderResponse{Err: errors2.E(errors2.Op("http.NewOrder"), remote)}, nil
+	}
+	var resp NewOrderResponse
+	err := json.NewDecoder(r.Body).Decode(&resp)
+	return resp, err
+}
+
+func copyURL(base *url.URL, path string) *url.URL {
+	next := *base
+	next.Path = path
+	return &next
+}
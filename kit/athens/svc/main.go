@@ -6,9 +6,14 @@ import (
 	"github.com/inContact/orch-common/orchlog"
 	orchlogflag "github.com/inContact/orch-common/orchlog/flag"
 	"github.com/jwenz723/errhandling/pb"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/errors/report"
+	"github.com/jwenz723/errhandling/pkg/errors/trace"
+	"github.com/jwenz723/errhandling/pkg/requestid"
 	"google.golang.org/grpc"
 	"gopkg.in/alecthomas/kingpin.v2"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"time"
@@ -20,6 +25,12 @@ func main() {
 
 	cfg := struct {
 		grpcAddr      string
+		httpAddr      string
+		statusErrors  bool
+		debugInfo     bool
+		reportFile    string
+		reportURL     string
+		printSpans    bool
 		orchlogConfig orchlog.Config
 	}{
 		orchlogConfig: orchlog.Config{},
@@ -27,9 +38,16 @@ func main() {
 
 	a := kingpin.New(filepath.Base(os.Args[0]), svcName)
 	a.Flag("grpc-addr", "gRPC listen address.").Short('g').Default(":9884").StringVar(&cfg.grpcAddr)
+	a.Flag("http-addr", "HTTP listen address.").Default(":9885").StringVar(&cfg.httpAddr)
+	a.Flag("grpc-status-errors", "Return failed responses as gRPC status errors.").BoolVar(&cfg.statusErrors)
+	a.Flag("status-debug-info", "Send stacks to clients in status details, for trusted deployments only.").BoolVar(&cfg.debugInfo)
+	a.Flag("report-file", "JSONL file server errors are reported to.").StringVar(&cfg.reportFile)
+	a.Flag("report-url", "HTTP endpoint server errors are reported to.").StringVar(&cfg.reportURL)
+	a.Flag("print-spans", "Print the spans recorded in memory before exiting.").BoolVar(&cfg.printSpans)
 	orchlogflag.AddFlags(a, &cfg.orchlogConfig)
 	_, err := a.Parse(os.Args[1:])
 	logger := orchlog.New(&cfg.orchlogConfig)
+	errors2.StatusDebugInfo = cfg.debugInfo
 
 	var (
 		endpointsLogger = log.With(logger,
@@ -40,11 +58,30 @@ func main() {
 		gRPCClientLogger = log.With(logger,
 			"component", "client",
 			"transport", "gRPC")
+		httpLogger = log.With(logger,
+			"component", "transport",
+			"transport", "HTTP")
+		httpClientLogger = log.With(logger,
+			"component", "client",
+			"transport", "HTTP")
 	)
 
+	reporters, closeReporters, err := report.Open(cfg.reportFile, cfg.reportURL)
+	if err != nil {
+		logger.Log("msg", "failed to open report file", "err", err)
+	}
+	defer closeReporters()
+
+	tracer := trace.NewInMemory()
+
 	svc := NewService()
-	endpoints := NewSet(svc, endpointsLogger)
-	grpcServer := NewGRPCServer(endpoints, gRPCLogger)
+	endpoints := NewSet(svc, endpointsLogger, reporters, tracer)
+	var grpcOpts []GRPCServerOption
+	if cfg.statusErrors {
+		grpcOpts = append(grpcOpts, StatusErrors())
+	}
+	grpcServer := NewGRPCServer(endpoints, tracer, gRPCLogger, grpcOpts...)
+	httpHandler := NewHTTPHandler(endpoints, tracer, httpLogger)
 
 	// Setup the server
 	grpcListener, err := net.Listen("tcp", cfg.grpcAddr)
@@ -57,15 +94,37 @@ func main() {
 		_ = baseServer.Serve(grpcListener)
 	}()
 
+	httpListener, err := net.Listen("tcp", cfg.httpAddr)
+	if err != nil {
+		panic(err)
+	}
+	go func() {
+		_ = http.Serve(httpListener, httpHandler)
+	}()
+
 	// Do a client request to the server
 	conn, err := grpc.Dial(cfg.grpcAddr, grpc.WithInsecure())
 	if err != nil {
 		panic(err)
 	}
-	s := NewGRPCClient(conn, gRPCClientLogger)
-	orderID, err := s.NewOrder(context.TODO(), "123")
-	gRPCClientLogger.Log("orderID", orderID, "err", err)
+	s := NewGRPCClient(conn, tracer, gRPCClientLogger)
+	ctx := requestid.NewContext(context.TODO(), requestid.New())
+	orderID, err := s.NewOrder(ctx, "123")
+	gRPCClientLogger.Log("orderID", orderID, "err", err, "request_id", errors2.LookupString(err, errors2.RequestIDField))
+
+	hc, err := NewHTTPClient(cfg.httpAddr, tracer, httpClientLogger)
+	if err != nil {
+		panic(err)
+	}
+	ctx = requestid.NewContext(context.TODO(), requestid.New())
+	orderID, err = hc.NewOrder(ctx, "123")
+	httpClientLogger.Log("orderID", orderID, "err", err, "request_id", errors2.LookupString(err, errors2.RequestIDField))
 
 	grpcListener.Close()
+	httpListener.Close()
 	time.Sleep(1 * time.Second)
+
+	if cfg.printSpans {
+		_ = tracer.Dump(os.Stdout)
+	}
 }
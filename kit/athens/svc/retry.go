@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+)
+
+// AttemptsField holds the number of attempts
+// made before a retried call gave up.
+const AttemptsField = errors2.Field("attempts")
+
+// Backoff configures RetryMiddleware.
+type Backoff struct {
+	// Attempts is the maximum number of
+	// calls made, including the first.
+	Attempts int
+	// Base is the delay before the first retry,
+	// it doubles with every further retry up to Max.
+	Base time.Duration
+	Max  time.Duration
+}
+
+// wait returns the delay before retry n (starting
+// at 0) using full jitter, so that clients failing
+// together don't retry together.
+func (b Backoff) wait(n int) time.Duration {
+	d := b.Max
+	if n < 32 && b.Base<<uint(n) < b.Max {
+		d = b.Base << uint(n)
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
+
+// errSetter is implemented by responses whose
+// endpoint.Failer error can be replaced, so that
+// RetryMiddleware can record every attempt in it.
+type errSetter interface {
+	withErr(err error) interface{}
+}
+
+// RetryMiddleware returns a client endpoint middleware
+// that retries calls failing with an error classified
+// as retryable by errors.Retryable, either from the
+// transport or returned by endpoint.Failer. Status
+// errors are decoded so that their Kind and RetryInfo
+// are known. A server sent RetryInfo delay is waited
+// out if it is longer than the backoff, and no retry is
+// made that can't start before the context deadline.
+//
+// When more than one attempt was made the final error
+// is an Error with op holding a MultiError of every
+// attempt and the number of attempts in AttemptsField.
+func RetryMiddleware(op errors2.Op, b Backoff) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			var attempts []error
+			for {
+				response, err := next(ctx, request)
+				ferr := failure(response, err)
+				if ferr == nil {
+					return response, nil
+				}
+				if remote, ok := errors2.FromError(ferr); ok {
+					ferr = remote
+				}
+				attempts = append(attempts, ferr)
+
+				if !errors2.Retryable(ferr) || len(attempts) >= b.Attempts || !sleep(ctx, retryWait(b, len(attempts)-1, ferr)) {
+					return finish(op, response, err, attempts)
+				}
+			}
+		}
+	}
+}
+
+// retryWait returns the delay before retry n,
+// which is at least the RetryInfo delay of err.
+func retryWait(b Backoff, n int, err error) time.Duration {
+	d := b.wait(n)
+	if ra := errors2.RetryAfter(err); ra > d {
+		d = ra
+	}
+	return d
+}
+
+// sleep waits for d unless ctx is done first or its
+// deadline would pass, reporting whether it waited.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		return false
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// finish returns the outcome of the last attempt with
+// its error replaced by one that records all attempts.
+func finish(op errors2.Op, response interface{}, err error, attempts []error) (interface{}, error) {
+	final := attempts[len(attempts)-1]
+	if len(attempts) > 1 {
+		final = errors2.E(op, &errors2.MultiError{Errors: attempts, Policy: errors2.LastKind}, AttemptsField.V(len(attempts)))
+	}
+	if err != nil {
+		return response, final
+	}
+	if s, ok := response.(errSetter); ok {
+		return s.withErr(final), nil
+	}
+	return response, nil
+}
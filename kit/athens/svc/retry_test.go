@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func TestRetryMiddleware(t *testing.T) {
+	unavailable := errors2.E("remote", "down", errors2.KindUnavailable)
+	badRequest := errors2.E("remote", "bad", errors2.KindBadRequest)
+	retryAfter := func(d time.Duration) error {
+		return errors2.E("remote", "busy", errors2.KindRateLimit, d)
+	}
+
+	tests := []struct {
+		name    string
+		backoff Backoff
+		timeout time.Duration
+		// errs are returned by the calls in turn,
+		// once they run out calls succeed.
+		errs []error
+		// failer returns errs in NewOrderResponse
+		// instead of as transport errors.
+		failer   bool
+		calls    int
+		attempts int // 0 when the call succeeds
+		minTime  time.Duration
+		maxTime  time.Duration
+	}{
+		{
+			name:     "capped at Attempts",
+			backoff:  Backoff{Attempts: 3, Base: time.Millisecond, Max: time.Millisecond},
+			errs:     []error{unavailable, unavailable, unavailable, unavailable},
+			calls:    3,
+			attempts: 3,
+		},
+		{
+			name:    "succeeds after a retry",
+			backoff: Backoff{Attempts: 3, Base: time.Millisecond, Max: time.Millisecond},
+			errs:    []error{unavailable},
+			calls:   2,
+		},
+		{
+			name:    "RetryInfo over backoff",
+			backoff: Backoff{Attempts: 2, Base: time.Nanosecond, Max: time.Nanosecond},
+			errs:    []error{retryAfter(50 * time.Millisecond)},
+			calls:   2,
+			minTime: 50 * time.Millisecond,
+		},
+		{
+			name:     "stops before the deadline",
+			backoff:  Backoff{Attempts: 3, Base: time.Millisecond, Max: time.Millisecond},
+			timeout:  100 * time.Millisecond,
+			errs:     []error{retryAfter(time.Second)},
+			calls:    1,
+			attempts: 1,
+			maxTime:  50 * time.Millisecond,
+		},
+		{
+			name:     "not retryable",
+			backoff:  Backoff{Attempts: 3, Base: time.Millisecond, Max: time.Millisecond},
+			errs:     []error{badRequest},
+			calls:    1,
+			attempts: 1,
+		},
+		{
+			name:     "records every attempt",
+			backoff:  Backoff{Attempts: 5, Base: time.Millisecond, Max: time.Millisecond},
+			errs:     []error{unavailable, retryAfter(time.Millisecond), badRequest},
+			calls:    3,
+			attempts: 3,
+		},
+		{
+			name:     "endpoint.Failer",
+			backoff:  Backoff{Attempts: 2, Base: time.Millisecond, Max: time.Millisecond},
+			errs:     []error{unavailable, unavailable},
+			failer:   true,
+			calls:    2,
+			attempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			ep := RetryMiddleware("client.Test", tt.backoff)(func(context.Context, interface{}) (interface{}, error) {
+				calls++
+				if calls > len(tt.errs) {
+					return NewOrderResponse{OrderID: "o-1"}, nil
+				}
+				if tt.failer {
+					return NewOrderResponse{Err: tt.errs[calls-1]}, nil
+				}
+				return nil, tt.errs[calls-1]
+			})
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			start := time.Now()
+			response, err := ep(ctx, nil)
+			elapsed := time.Since(start)
+
+			if tt.failer {
+				if err != nil {
+					t.Fatalf("transport error %v, want it in the response", err)
+				}
+				err = response.(NewOrderResponse).Err
+			}
+			if calls != tt.calls {
+				t.Errorf("next was called %d times, want %d", calls, tt.calls)
+			}
+			if tt.minTime > 0 && elapsed < tt.minTime {
+				t.Errorf("took %v, want at least %v", elapsed, tt.minTime)
+			}
+			if tt.maxTime > 0 && elapsed > tt.maxTime {
+				t.Errorf("took %v, want at most %v", elapsed, tt.maxTime)
+			}
+
+			switch {
+			case tt.attempts == 0:
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+			case tt.attempts == 1:
+				if err != tt.errs[0] {
+					t.Errorf("err = %v, want the error of the only attempt", err)
+				}
+			default:
+				errs := errors2.Errors(err)
+				if len(errs) != tt.attempts {
+					t.Fatalf("%d attempts recorded, want %d", len(errs), tt.attempts)
+				}
+				for i, e := range errs {
+					if e != tt.errs[i] {
+						t.Errorf("attempt %d = %v, want %v", i, e, tt.errs[i])
+					}
+				}
+				if v, _ := errors2.Lookup(err, AttemptsField); v != tt.attempts {
+					t.Errorf("%s = %v, want %d", AttemptsField, v, tt.attempts)
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,9 @@ package main
 
 import (
 	"context"
-	errors2 "github.com/jwenz723/errhandling/kit/athens/errors"
+	errors2 "github.com/jwenz723/errhandling/pkg/errors"
 	"github.com/jwenz723/errhandling/pkg/errorthrower"
+	"github.com/jwenz723/errhandling/pkg/ordercodes"
 	"golang.org/x/xerrors"
 )
 
@@ -22,12 +23,12 @@ func NewService() orderService {
 func (orderService) NewOrder(ctx context.Context, customerID string) (string, error) {
 	const op = errors2.Op("service.NewOrder")
 	if customerID == "" {
-		return "", ErrEmpty
+		return "", errors2.E(op, ErrEmpty, ordercodes.CustomerRequired)
 	}
 
 	err := errorthrower.SomeError()
 	if err != nil {
-		return "", errors2.E(op, err, errors2.C(customerID), errors2.KindBadRequest)
+		return "", errors2.EC(ctx, op, err, ordercodes.OrderRejected)
 	}
 
 	return "my order id", nil
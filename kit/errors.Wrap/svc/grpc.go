@@ -50,18 +50,25 @@ func decodeGRPCNewOrderRequest(_ context.Context, grpcReq interface{}) (interfac
 // user-domain sum response to a gRPC sum reply. Primarily useful in a server.
 func encodeGRPCNewOrderResponse(_ context.Context, response interface{}) (interface{}, error) {
 	resp := response.(NewOrderResponse)
-	return &pb.NewOrderReply{OrderID: resp.OrderID, Err: errors.Wrap(resp.Err, "grpc.NewOrder").Error()}, nil
+	return &pb.NewOrderReply{OrderID: resp.OrderID, Err: err2pb(resp.Err)}, nil
 }
 
 // These annoying helper functions are required to translate Go error types to
-// and from strings, which is the type we use in our IDLs to represent errors.
-// There is special casing to treat empty strings as nil errors.
+// and from pb.Error, which is the type we use in our IDLs to represent errors.
+// There is special casing to treat nil pb.Errors as nil errors.
 
-func str2err(s string) error {
-	if s == "" {
+func pb2err(e *pb.Error) error {
+	if e == nil {
 		return nil
 	}
-	return errors.New(s)
+	return errors.New(e.Message)
+}
+
+func err2pb(err error) *pb.Error {
+	if err == nil {
+		return nil
+	}
+	return &pb.Error{Message: errors.Wrap(err, "grpc.NewOrder").Error()}
 }
 
 func NewGRPCClient(conn *grpc.ClientConn, logger log.Logger) OrderService {
@@ -92,5 +99,5 @@ func encodeGRPCNewOrderRequest(_ context.Context, request interface{}) (interfac
 
 func decodeGRPCNewOrderResponse(_ context.Context, grpcReply interface{}) (interface{}, error) {
 	reply := grpcReply.(*pb.NewOrderReply)
-	return NewOrderResponse{OrderID: reply.OrderID, Err: str2err(reply.Err)}, nil
+	return NewOrderResponse{OrderID: reply.OrderID, Err: pb2err(reply.Err)}, nil
 }
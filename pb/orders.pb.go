@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -65,7 +66,7 @@ func (m *NewOrderRequest) GetCustomerID() string {
 
 type NewOrderReply struct {
 	OrderID              string   `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
-	Err                  string   `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	Err                  *Error   `protobuf:"bytes,3,opt,name=err,proto3" json:"err,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -103,32 +104,244 @@ func (m *NewOrderReply) GetOrderID() string {
 	return ""
 }
 
-func (m *NewOrderReply) GetErr() string {
+func (m *NewOrderReply) GetErr() *Error {
 	if m != nil {
 		return m.Err
 	}
+	return nil
+}
+
+// Error is an athens error as it crosses the wire.
+type Error struct {
+	// code is the stable catalog code, if any.
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// kind is the athens Kind, an HTTP status.
+	Kind int32 `protobuf:"varint,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// message is the error's text with redacted fields replaced.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// ops is the Op chain, outermost first.
+	Ops []string `protobuf:"bytes,4,rep,name=ops,proto3" json:"ops,omitempty"`
+	// fields holds the redacted field values. Values are
+	// sent in their fmt.Sprint form, so they come back as
+	// strings whatever their type was.
+	Fields       map[string]string `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Severity     string            `protobuf:"bytes,6,opt,name=severity,proto3" json:"severity,omitempty"`
+	Retryable    bool              `protobuf:"varint,7,opt,name=retryable,proto3" json:"retryable,omitempty"`
+	RetryAfterMs int64             `protobuf:"varint,8,opt,name=retryAfterMs,proto3" json:"retryAfterMs,omitempty"`
+	// grpcMsg is the explicit client facing message, if any,
+	// with redacted fields replaced.
+	GrpcMsg string `protobuf:"bytes,9,opt,name=grpcMsg,proto3" json:"grpcMsg,omitempty"`
+	// grpcCode is the explicit gRPC code, if any.
+	GrpcCode *wrappers.Int32Value `protobuf:"bytes,10,opt,name=grpcCode,proto3" json:"grpcCode,omitempty"`
+	// badRequest holds the field violations of a bad request.
+	BadRequest []*FieldViolation `protobuf:"bytes,11,rep,name=badRequest,proto3" json:"badRequest,omitempty"`
+	// errors holds the children of a MultiError in the chain,
+	// whose Op, if any, is the last of ops. Each child has
+	// its own ops, below those of its parent.
+	Errors               []*Error `protobuf:"bytes,12,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Error) Reset()         { *m = Error{} }
+func (m *Error) String() string { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()    {}
+func (*Error) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e0f5d4cf0fc9e41b, []int{2}
+}
+
+func (m *Error) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Error.Unmarshal(m, b)
+}
+func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+}
+func (m *Error) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Error.Merge(m, src)
+}
+func (m *Error) XXX_Size() int {
+	return xxx_messageInfo_Error.Size(m)
+}
+func (m *Error) XXX_DiscardUnknown() {
+	xxx_messageInfo_Error.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Error proto.InternalMessageInfo
+
+func (m *Error) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *Error) GetKind() int32 {
+	if m != nil {
+		return m.Kind
+	}
+	return 0
+}
+
+func (m *Error) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Error) GetOps() []string {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+func (m *Error) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *Error) GetSeverity() string {
+	if m != nil {
+		return m.Severity
+	}
+	return ""
+}
+
+func (m *Error) GetRetryable() bool {
+	if m != nil {
+		return m.Retryable
+	}
+	return false
+}
+
+func (m *Error) GetRetryAfterMs() int64 {
+	if m != nil {
+		return m.RetryAfterMs
+	}
+	return 0
+}
+
+func (m *Error) GetGrpcMsg() string {
+	if m != nil {
+		return m.GrpcMsg
+	}
+	return ""
+}
+
+func (m *Error) GetGrpcCode() *wrappers.Int32Value {
+	if m != nil {
+		return m.GrpcCode
+	}
+	return nil
+}
+
+func (m *Error) GetBadRequest() []*FieldViolation {
+	if m != nil {
+		return m.BadRequest
+	}
+	return nil
+}
+
+func (m *Error) GetErrors() []*Error {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+// FieldViolation describes a single bad request field.
+type FieldViolation struct {
+	Field                string   `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Description          string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FieldViolation) Reset()         { *m = FieldViolation{} }
+func (m *FieldViolation) String() string { return proto.CompactTextString(m) }
+func (*FieldViolation) ProtoMessage()    {}
+func (*FieldViolation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e0f5d4cf0fc9e41b, []int{3}
+}
+
+func (m *FieldViolation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FieldViolation.Unmarshal(m, b)
+}
+func (m *FieldViolation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FieldViolation.Marshal(b, m, deterministic)
+}
+func (m *FieldViolation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FieldViolation.Merge(m, src)
+}
+func (m *FieldViolation) XXX_Size() int {
+	return xxx_messageInfo_FieldViolation.Size(m)
+}
+func (m *FieldViolation) XXX_DiscardUnknown() {
+	xxx_messageInfo_FieldViolation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FieldViolation proto.InternalMessageInfo
+
+func (m *FieldViolation) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *FieldViolation) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
 	return ""
 }
 
 func init() {
 	proto.RegisterType((*NewOrderRequest)(nil), "pb.NewOrderRequest")
 	proto.RegisterType((*NewOrderReply)(nil), "pb.NewOrderReply")
+	proto.RegisterType((*Error)(nil), "pb.Error")
+	proto.RegisterMapType((map[string]string)(nil), "pb.Error.FieldsEntry")
+	proto.RegisterType((*FieldViolation)(nil), "pb.FieldViolation")
 }
 
 func init() { proto.RegisterFile("orders.proto", fileDescriptor_e0f5d4cf0fc9e41b) }
 
 var fileDescriptor_e0f5d4cf0fc9e41b = []byte{
-	// 149 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xc9, 0x2f, 0x4a, 0x49,
-	0x2d, 0x2a, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x2a, 0x48, 0x52, 0x32, 0xe4, 0xe2,
-	0xf7, 0x4b, 0x2d, 0xf7, 0x07, 0x09, 0x07, 0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97, 0x08, 0xc9, 0x71,
-	0x71, 0x25, 0x97, 0x16, 0x97, 0xe4, 0xe7, 0xa6, 0x16, 0x79, 0xba, 0x48, 0x30, 0x2a, 0x30, 0x6a,
-	0x70, 0x06, 0x21, 0x89, 0x28, 0x59, 0x73, 0xf1, 0x22, 0xb4, 0x14, 0xe4, 0x54, 0x0a, 0x49, 0x70,
-	0xb1, 0x83, 0xcd, 0x85, 0xab, 0x86, 0x71, 0x85, 0x04, 0xb8, 0x98, 0x53, 0x8b, 0x8a, 0x24, 0x98,
-	0xc0, 0xa2, 0x20, 0xa6, 0x91, 0x1d, 0x17, 0x1b, 0x58, 0x67, 0xb1, 0x90, 0x09, 0x17, 0x07, 0xcc,
-	0x18, 0x21, 0x61, 0xbd, 0x82, 0x24, 0x3d, 0x34, 0x77, 0x48, 0x09, 0xa2, 0x0a, 0x16, 0xe4, 0x54,
-	0x2a, 0x31, 0x24, 0xb1, 0x81, 0x9d, 0x6e, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0xe5, 0x04, 0xec,
-	0x9c, 0xca, 0x00, 0x00, 0x00,
+	// 458 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x52, 0x4f, 0x6f, 0xd3, 0x4e,
+	0x10, 0xfd, 0x39, 0x4e, 0x52, 0x7b, 0x92, 0x1f, 0x94, 0x01, 0xa4, 0x55, 0x8a, 0x2a, 0xe3, 0x93,
+	0x2f, 0xb8, 0xc2, 0x45, 0xe2, 0xcf, 0x01, 0x09, 0x41, 0x11, 0x45, 0x14, 0xa4, 0x3d, 0xf4, 0x6e,
+	0xc7, 0x13, 0xcb, 0xaa, 0x9b, 0x5d, 0x66, 0x9d, 0x56, 0xfe, 0x90, 0x7c, 0x27, 0xb4, 0x1b, 0xc7,
+	0x4d, 0xb8, 0xcd, 0x7b, 0xfb, 0x76, 0xfe, 0xbd, 0x81, 0xb9, 0xe2, 0x92, 0xd8, 0xa4, 0x9a, 0x55,
+	0xab, 0x70, 0xa4, 0x8b, 0xc5, 0x69, 0xa5, 0x54, 0xd5, 0xd0, 0x99, 0x63, 0x8a, 0xcd, 0xea, 0xec,
+	0x9e, 0x73, 0xad, 0x07, 0x4d, 0xfc, 0x1a, 0x1e, 0xff, 0xa4, 0xfb, 0x5f, 0xf6, 0x9b, 0xa4, 0xdf,
+	0x1b, 0x32, 0x2d, 0x9e, 0x02, 0x2c, 0x37, 0xa6, 0x55, 0xb7, 0xc4, 0x97, 0x5f, 0x84, 0x17, 0x79,
+	0x49, 0x28, 0xf7, 0x98, 0xf8, 0x07, 0xfc, 0xff, 0xf0, 0x45, 0x37, 0x1d, 0x0a, 0x38, 0x72, 0x75,
+	0x07, 0xf5, 0x0e, 0xe2, 0x09, 0xf8, 0xc4, 0x2c, 0xfc, 0xc8, 0x4b, 0x66, 0x59, 0x98, 0xea, 0x22,
+	0xbd, 0x60, 0x56, 0x2c, 0x2d, 0xfb, 0x7d, 0x1c, 0x8c, 0x8e, 0xfd, 0xf8, 0x8f, 0x0f, 0x13, 0x47,
+	0x22, 0xc2, 0x78, 0xa9, 0x4a, 0xea, 0x73, 0xb8, 0xd8, 0x72, 0x37, 0xf5, 0xba, 0x14, 0xa3, 0xc8,
+	0x4b, 0x26, 0xd2, 0xc5, 0xb6, 0xdc, 0x2d, 0x19, 0x93, 0x57, 0xe4, 0x12, 0x87, 0x72, 0x07, 0xf1,
+	0x18, 0x7c, 0xa5, 0x8d, 0x18, 0x47, 0x7e, 0x12, 0x4a, 0x1b, 0xe2, 0x2b, 0x98, 0xae, 0x6a, 0x6a,
+	0x4a, 0x23, 0x26, 0x91, 0x9f, 0xcc, 0xb2, 0xe7, 0x43, 0x0f, 0xe9, 0x57, 0xc7, 0x5f, 0xac, 0x5b,
+	0xee, 0x64, 0x2f, 0xc2, 0x05, 0x04, 0x86, 0xee, 0x88, 0xeb, 0xb6, 0x13, 0x53, 0x97, 0x7b, 0xc0,
+	0xf8, 0x02, 0x42, 0xa6, 0x96, 0xbb, 0xbc, 0x68, 0x48, 0x1c, 0x45, 0x5e, 0x12, 0xc8, 0x07, 0x02,
+	0x63, 0x98, 0x3b, 0xf0, 0x69, 0xd5, 0x12, 0x5f, 0x19, 0x11, 0x44, 0x5e, 0xe2, 0xcb, 0x03, 0xce,
+	0x36, 0x5e, 0xb1, 0x5e, 0x5e, 0x99, 0x4a, 0x84, 0xdb, 0xc6, 0x7b, 0x88, 0x6f, 0x21, 0xb0, 0xe1,
+	0x67, 0x3b, 0x3e, 0xb8, 0x65, 0x9d, 0xa4, 0x5b, 0xe3, 0xd2, 0x9d, 0x71, 0xe9, 0xe5, 0xba, 0x3d,
+	0xcf, 0xae, 0xf3, 0x66, 0x43, 0x72, 0x10, 0x63, 0x06, 0x50, 0xe4, 0x65, 0xef, 0x9c, 0x98, 0xb9,
+	0x19, 0xd1, 0xce, 0xe8, 0xa6, 0xbb, 0xae, 0x55, 0x93, 0xb7, 0xb5, 0x5a, 0xcb, 0x3d, 0x15, 0xbe,
+	0x84, 0x29, 0x31, 0x2b, 0x36, 0x62, 0x1e, 0xf9, 0x87, 0xbe, 0xf4, 0x0f, 0x8b, 0xf7, 0x30, 0xdb,
+	0x5b, 0x8f, 0xdd, 0xeb, 0x0d, 0x75, 0xbd, 0x31, 0x36, 0xc4, 0x67, 0x30, 0xb9, 0xb3, 0xad, 0x38,
+	0x63, 0x42, 0xb9, 0x05, 0x1f, 0x46, 0xef, 0xbc, 0xf8, 0x1b, 0x3c, 0x3a, 0xac, 0x6d, 0xb5, 0x6e,
+	0xbd, 0xfd, 0xff, 0x2d, 0xc0, 0x08, 0x66, 0x25, 0x99, 0x25, 0xd7, 0xda, 0x8a, 0xfa, 0x3c, 0xfb,
+	0x54, 0xf6, 0x11, 0xa6, 0xee, 0xc8, 0x0c, 0xbe, 0x81, 0x60, 0x77, 0x71, 0xf8, 0xd4, 0x76, 0xfb,
+	0xcf, 0xc9, 0x2e, 0x9e, 0x1c, 0x92, 0xba, 0xe9, 0xe2, 0xff, 0x8a, 0xa9, 0x5b, 0xdd, 0xf9, 0xdf,
+	0x01, 0x00, 0xb4, 0x18, 0x69, 0x82, 0x15, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
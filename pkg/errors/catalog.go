@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"text/template"
+
+	"github.com/go-kit/kit/log/level"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorCode is a stable, client facing name for
+// a specific failure, e.g. ORDERS_CUSTOMER_REQUIRED.
+// Unlike a Kind it can be part of an API contract.
+// Passing a registered ErrorCode to E fills in the
+// Kind, severity and gRPC code from its CodeDef.
+type ErrorCode string
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+// CodeDef describes a registered ErrorCode.
+type CodeDef struct {
+	Code ErrorCode
+	Kind int
+	// GrpcCode overrides the code mapped from
+	// Kind by KindCodes when it is not codes.OK.
+	GrpcCode codes.Code
+	// Message is a text/template rendered with the
+	// error's redacted fields, e.g. "order {{.orderID}}
+	// not found". It becomes the client facing message.
+	// When a field it uses is missing the default
+	// message of Kind is used instead.
+	Message  string
+	Severity level.Value
+	// Description documents when the code is returned.
+	Description string
+
+	tmpl *template.Template
+}
+
+var catalog = struct {
+	sync.RWMutex
+	defs map[ErrorCode]CodeDef
+}{defs: map[ErrorCode]CodeDef{}}
+
+// Register adds defs to the catalog. It panics if
+// a code is registered twice or a message template
+// doesn't parse, so it belongs in an init func.
+func Register(defs ...CodeDef) {
+	catalog.Lock()
+	defer catalog.Unlock()
+	for _, def := range defs {
+		if _, ok := catalog.defs[def.Code]; ok {
+			panic(fmt.Sprintf("errors: code %s registered twice", def.Code))
+		}
+		if def.Message != "" {
+			def.tmpl = template.Must(template.New(string(def.Code)).Option("missingkey=error").Parse(def.Message))
+		}
+		catalog.defs[def.Code] = def
+	}
+}
+
+// Def returns the CodeDef registered for code.
+func Def(code ErrorCode) (CodeDef, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	def, ok := catalog.defs[code]
+	return def, ok
+}
+
+// Catalog returns every registered
+// CodeDef ordered by code.
+func Catalog() []CodeDef {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	defs := make([]CodeDef, 0, len(catalog.defs))
+	for _, def := range catalog.defs {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Code < defs[j].Code })
+	return defs
+}
+
+// CodeOf returns the first ErrorCode
+// found in err's chain.
+func CodeOf(err error) ErrorCode {
+	e, _ := findError(err, func(e Error) bool { return e.Code != "" })
+	return e.Code
+}
+
+// render executes the message template of def with kvs.
+// It returns false if def has no message or one of the
+// fields the message uses is missing from kvs.
+func (def CodeDef) render(kvs []KV) (string, bool) {
+	if def.tmpl == nil {
+		return "", false
+	}
+	data := map[string]interface{}{}
+	for _, kv := range kvs {
+		data[kv.Field.String()] = kv.Value
+	}
+	var buf bytes.Buffer
+	if err := def.tmpl.Execute(&buf, data); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// applyCode fills in whatever e doesn't
+// set itself from the CodeDef of e.Code.
+func (e *Error) applyCode() {
+	def, ok := Def(e.Code)
+	if !ok {
+		return
+	}
+	if e.Kind == 0 {
+		e.Kind = def.Kind
+	}
+	if e.Severity == nil {
+		e.Severity = def.Severity
+	}
+	if e.GrpcCode == nil && def.GrpcCode != codes.OK {
+		c := def.GrpcCode
+		e.GrpcCode = &c
+	}
+}
@@ -0,0 +1,34 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+const testOrderRejected errors.ErrorCode = "TEST_ORDER_REJECTED"
+
+func init() {
+	errors.Register(errors.CodeDef{
+		Code:    testOrderRejected,
+		Kind:    errors.KindBadRequest,
+		Message: "order {{.orderID}} was rejected",
+	})
+}
+
+func TestCatalogMessage(t *testing.T) {
+	err := errors.E("server.NewOrder", testOrderRejected, errors.O("o-1"))
+	if got, want := errors.StatusMsg(err), errors.GM("order o-1 was rejected"); got != want {
+		t.Errorf("StatusMsg = %q, want %q", got, want)
+	}
+}
+
+func TestCatalogMessageMissingField(t *testing.T) {
+	err := errors.E("server.NewOrder", testOrderRejected)
+	if got, want := errors.StatusMsg(err), errors.KindMessages[errors.KindBadRequest]; got != want {
+		t.Errorf("StatusMsg = %q, want %q", got, want)
+	}
+	if got, want := err.Error(), errors.KindText(err); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package errors
+
+import "context"
+
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx that carries kvs
+// along with any fields ctx already carries, so that
+// attributes such as a customer ID can be set once at
+// the edge and picked up by every EC further down.
+// A Field set again replaces its earlier value.
+func WithFields(ctx context.Context, kvs ...KV) context.Context {
+	all := append([]KV(nil), FieldsFrom(ctx)...)
+next:
+	for _, kv := range kvs {
+		for i := range all {
+			if all[i].Field == kv.Field {
+				all[i] = kv
+				continue next
+			}
+		}
+		all = append(all, kv)
+	}
+	return context.WithValue(ctx, fieldsKey{}, all)
+}
+
+// FieldsFrom returns the fields carried by ctx.
+func FieldsFrom(ctx context.Context) []KV {
+	kvs, _ := ctx.Value(fieldsKey{}).([]KV)
+	return kvs
+}
+
+// EC is like E but also attaches the fields carried
+// by ctx. Fields passed in args win, and fields already
+// attached further down the wrapped error's chain are
+// not attached again.
+func EC(ctx context.Context, op Op, args ...interface{}) Error {
+	var kvs []interface{}
+	for _, kv := range FieldsFrom(ctx) {
+		if !attached(args, kv.Field) {
+			kvs = append(kvs, kv)
+		}
+	}
+	return build(op, append(kvs, args...))
+}
+
+// attached reports whether f is attached
+// to an error passed in args.
+func attached(args []interface{}, f Field) bool {
+	for _, a := range args {
+		if err, ok := a.(error); ok {
+			if _, ok := Lookup(err, f); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
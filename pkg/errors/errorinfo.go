@@ -0,0 +1,32 @@
+package errors
+
+import "github.com/golang/protobuf/proto"
+
+// ErrorInfo is the google.rpc.ErrorInfo detail message.
+//
+// The version of genproto this module depends on predates
+// ErrorInfo, so it is declared here with the same field
+// numbers as google/rpc/error_details.proto. It is encoded
+// on the wire exactly like the upstream message so that
+// clients in any language can decode it.
+type ErrorInfo struct {
+	// Reason is a constant value that identifies the
+	// proximate cause of the error, e.g. NOT_FOUND.
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Domain is the logical grouping the Reason belongs to.
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Metadata holds additional structured details
+	// such as the Kind, the Op chain and IDs.
+	Metadata map[string]string `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *ErrorInfo) Reset()         { *m = ErrorInfo{} }
+func (m *ErrorInfo) String() string { return proto.CompactTextString(m) }
+func (*ErrorInfo) ProtoMessage()    {}
+
+// XXX_MessageName gives ErrorInfo its fully qualified
+// proto name so that it can be packed into an Any
+// without being registered.
+func (*ErrorInfo) XXX_MessageName() string { return errorInfoName }
+
+const errorInfoName = "google.rpc.ErrorInfo"
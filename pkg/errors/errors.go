@@ -0,0 +1,632 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-kit/kit/log/level"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"io"
+	"net/http"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Kind enums
+const (
+	KindNotFound         = http.StatusNotFound
+	KindBadRequest       = http.StatusBadRequest
+	KindUnexpected       = http.StatusInternalServerError
+	KindAlreadyExists    = http.StatusConflict
+	KindRateLimit        = http.StatusTooManyRequests
+	KindNotImplemented   = http.StatusNotImplemented
+	KindRedirect         = http.StatusMovedPermanently
+	KindUnavailable      = http.StatusServiceUnavailable
+	KindDeadlineExceeded = http.StatusGatewayTimeout
+)
+
+// Error is an Athens system error.
+// It carries information and behavior
+// as to what caused this error so that
+// callers can implement logic around it.
+type Error struct {
+	// Kind categories Athens errors into a smaller
+	// subset of errors. This way we can generalize
+	// what an error really is: such as "not found",
+	// "bad request", etc. The official categories
+	// are HTTP status code but the ones we use are
+	// imported into this package.
+	Kind     int
+	Code     ErrorCode
+	Op       Op
+	Err      error
+	Severity level.Value
+	GrpcCode *codes.Code
+	GrpcMsg  GM
+	// BadRequest collects the field violations
+	// that caused a KindBadRequest error.
+	BadRequest *errdetails.BadRequest
+	// RetryAfter tells clients how long to wait
+	// before retrying the failed request.
+	RetryAfter time.Duration
+	// Retry overrides the retryability
+	// the Error gets from its Kind.
+	Retry *Retry
+	// fields holds the values attached with Field.V.
+	// It is a pointer so that Error stays comparable.
+	fields *[]KV
+	*stack
+}
+
+// Error returns the underlying error's
+// string message. The logger takes care
+// of filling out the stack levels and
+// extra information.
+func (e Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error so
+// that the standard library's errors.Is and
+// errors.As can traverse past an Error.
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+// Format implements a custom formatter to achieve printing of `stack` when `%+v` is used as a formatting verb.
+// The message is passed through RedactText so that formatted errors don't leak redacted fields.
+func (e Error) Format(s fmt.State, verb rune) {
+	msg := RedactText(e, e.Error())
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, msg)
+			innerStack(e).Format(s, verb)
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, msg)
+	case 'q':
+		fmt.Fprintf(s, "%q", msg)
+	}
+}
+
+// IsKind is a shorthand for checking an error against a kind.
+func IsKind(err error, kind int) bool {
+	if err == nil {
+		return false
+	}
+	return Kind(err) == kind
+}
+
+// Is reports whether any error in err's chain
+// matches target. It defers to the standard
+// library so that importers of this package
+// don't also need to import "errors".
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain
+// that matches target, and if so, sets target
+// to that error value and returns true. It
+// defers to the standard library.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap returns the result of calling the
+// Unwrap method on err, if err's type contains
+// an Unwrap method returning error. Otherwise,
+// Unwrap returns nil.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
+
+// Op describes any independent function or
+// method in Athens. A series of operations
+// forms a more readable stack trace.
+type Op string
+
+func (o Op) String() string {
+	return string(o)
+}
+
+// C represents a customerID. It is
+// a shorthand for CustomerIDField.V.
+type C string
+
+// O represents an orderID. It is
+// a shorthand for OrderIDField.V.
+type O string
+
+// GM represents a GrpcMsg
+type GM string
+
+// E is a helper function to construct an Error type
+// Operation always comes first, if it is empty then it
+// is derived from the calling function and line. Args must
+// have at least an error or a string to describe what exactly
+// went wrong. You can optionally pass a go-kit level to indicate
+// the log level of an error based on the context it was constructed in,
+// field violations to describe a bad request, a time.Duration
+// to tell clients when to retry, a Retry to override whether it
+// is safe to retry, and an ErrorCode from the catalog.
+func E(op Op, args ...interface{}) Error {
+	return build(op, args)
+}
+
+// build constructs the Error for E and EC. It must
+// be called directly by them so that the caller
+// depth used to derive the Op and stack is right,
+// whether or not E and EC are inlined.
+func build(op Op, args []interface{}) Error {
+	e := Error{Op: op}
+	if len(args) == 0 {
+		msg := "errors.E called with 0 args"
+		if f, ok := caller(1); ok {
+			msg = fmt.Sprintf("%v - %v:%v", msg, f.File, f.Line)
+		}
+		e.Err = errors.New(msg)
+	}
+	for _, a := range args {
+		switch a := a.(type) {
+		case MultiError:
+			// a MultiError value would make
+			// comparing the Error panic.
+			e.Err = &a
+		case error:
+			e.Err = a
+		case string:
+			e.Err = errors.New(a)
+		case KV:
+			e.addField(a)
+		case C:
+			e.addField(CustomerIDField.V(string(a)))
+		case O:
+			e.addField(OrderIDField.V(string(a)))
+		case GM:
+			e.GrpcMsg = a
+		case codes.Code:
+			e.GrpcCode = &a
+		case *errdetails.BadRequest_FieldViolation:
+			if e.BadRequest == nil {
+				e.BadRequest = &errdetails.BadRequest{}
+			}
+			e.BadRequest.FieldViolations = append(e.BadRequest.FieldViolations, a)
+		case time.Duration:
+			e.RetryAfter = a
+		case Retry:
+			e.Retry = &a
+		case level.Value:
+			e.Severity = a
+		case ErrorCode:
+			e.Code = a
+		case int:
+			e.Kind = a
+		}
+	}
+	if e.Code != "" {
+		e.applyCode()
+	}
+	if e.Err == nil {
+		msg := KindText(e)
+		if def, ok := Def(e.Code); ok {
+			if m, ok := def.render(Fields(e)); ok {
+				msg = m
+			}
+		}
+		e.Err = errors.New(msg)
+	}
+
+	// a chain only needs the stack of its inner-most
+	// Error, unless every layer should have its own.
+	switch CaptureStack {
+	case StackAlways:
+		e.stack = callers()
+	case StackInnermost:
+		if !hasStack(e.Err) {
+			e.stack = callers()
+		}
+	}
+	if e.Op == "" {
+		if f, ok := caller(1); ok {
+			e.Op = Op(fmt.Sprintf("%s:%d", funcname(f.Function), f.Line))
+		}
+	}
+	return e
+}
+
+// caller returns the frame skip frames above the
+// caller of the function calling it, so caller(1)
+// in build is the caller of E or EC. Frames are
+// resolved with runtime.CallersFrames, which unlike
+// runtime.FuncForPC accounts for inlined calls.
+func caller(skip int) (runtime.Frame, bool) {
+	var pcs [16]uintptr
+	// skip runtime.Callers and caller itself.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for i := 0; ; i++ {
+		f, more := frames.Next()
+		if i == skip+1 {
+			return f, f.Function != ""
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// Severity returns the log level of an error.
+// The outermost severity set in err's chain wins,
+// a MultiError uses its most severe child, and if
+// none exists then the default for its Kind is
+// used, which is Error for unexpected errors.
+func Severity(err error) level.Value {
+	src, ok := Find(err, func(err error) bool {
+		if e, ok := asError(err); ok {
+			return e.Severity != nil
+		}
+		_, ok := asMulti(err)
+		return ok
+	})
+	if !ok {
+		return KindSeverity(Kind(err))
+	}
+
+	if m, ok := asMulti(src); ok {
+		return m.severity()
+	}
+	e, _ := asError(src)
+	return e.Severity
+}
+
+// Expect is a helper that returns an Info level
+// if the error has the expected kind, otherwise
+// it returns the error's resolved Severity.
+func Expect(err error, kinds ...int) level.Value {
+	for _, kind := range kinds {
+		if Kind(err) == kind {
+			return level.InfoValue()
+		}
+	}
+	return Severity(err)
+}
+
+// CustomerID returns the first customerID
+// found in err's chain.
+func CustomerID(err error) C {
+	return C(LookupString(err, CustomerIDField))
+}
+
+// OrderID returns the first orderID
+// found in err's chain.
+func OrderID(err error) O {
+	return O(LookupString(err, OrderIDField))
+}
+
+// GrpcCode returns the first gRPC code
+// found in err's chain.
+func GrpcCode(err error) *codes.Code {
+	e, _ := findError(err, func(e Error) bool { return e.GrpcCode != nil })
+	return e.GrpcCode
+}
+
+// GrpcMsg returns the first gRPC message
+// found in err's chain.
+func GrpcMsg(err error) GM {
+	e, _ := findError(err, func(e Error) bool { return e.GrpcMsg != "" })
+	return e.GrpcMsg
+}
+
+// BadRequest returns the first set of
+// field violations found in err's chain.
+func BadRequest(err error) *errdetails.BadRequest {
+	e, _ := findError(err, func(e Error) bool { return e.BadRequest != nil })
+	return e.BadRequest
+}
+
+// RetryAfter returns the first retry
+// delay found in err's chain.
+func RetryAfter(err error) time.Duration {
+	e, _ := findError(err, func(e Error) bool { return e.RetryAfter > 0 })
+	return e.RetryAfter
+}
+
+// Kind searches err's chain for the
+// first error kind it finds. A MultiError
+// resolves its Kind from its children. If
+// there is none, the kind is derived from
+// any gRPC status error in the chain.
+func Kind(err error) int {
+	src, ok := kindSource(err)
+	if !ok {
+		return KindUnexpected
+	}
+
+	if e, ok := asError(src); ok {
+		return e.Kind
+	}
+	if m, ok := asMulti(src); ok {
+		return m.kind()
+	}
+
+	// fall back to the code of a status
+	// error received from a downstream call.
+	st, _ := statusOf(src)
+	return KindFromCode(st.Code())
+}
+
+// kindSource returns the first error in err's
+// chain that decides its Kind: an Error with a
+// Kind, a MultiError or a downstream status error.
+func kindSource(err error) (error, bool) {
+	return Find(err, func(err error) bool {
+		if e, ok := asError(err); ok {
+			return e.Kind != 0
+		}
+		if _, ok := asMulti(err); ok {
+			return true
+		}
+		_, ok := statusOf(err)
+		return ok
+	})
+}
+
+// KindText returns a friendly string
+// of the Kind type. Since we use http
+// status codes to represent error kinds,
+// this method just deferrs to the net/http
+// text representations of statuses.
+func KindText(err error) string {
+	return http.StatusText(Kind(err))
+}
+
+// Ops aggregates the error's operation
+// with all the embedded errors' operations.
+// This way you can construct a queryable
+// stack trace. A MultiError adds its own Op
+// followed by the Ops of each child in turn.
+func Ops(err error) []Op {
+	var ops []Op
+	Walk(err, func(err error) bool {
+		if e, ok := asError(err); ok && e.Op != "" {
+			ops = append(ops, e.Op)
+		}
+		if m, ok := asMulti(err); ok {
+			if m.Op != "" {
+				ops = append(ops, m.Op)
+			}
+			for _, child := range m.Errors {
+				ops = append(ops, Ops(child)...)
+			}
+		}
+		return true
+	})
+	return ops
+}
+
+// chainOps is like Ops but leaves out
+// the Ops of the children of a MultiError.
+func chainOps(err error) []Op {
+	var ops []Op
+	Walk(err, func(err error) bool {
+		if e, ok := asError(err); ok && e.Op != "" {
+			ops = append(ops, e.Op)
+		}
+		if m, ok := asMulti(err); ok && m.Op != "" {
+			ops = append(ops, m.Op)
+		}
+		return true
+	})
+	return ops
+}
+
+// OpsText joins the result of Ops
+// into a single string.
+func OpsText(err error) string {
+	var ops []string
+	for _, op := range Ops(err) {
+		ops = append(ops, string(op))
+	}
+	return strings.Join(ops, ": ")
+}
+
+// Stack returns the inner-most stack
+// captured in err's chain, if any.
+func Stack(err error) StackTrace {
+	return innerStack(err).StackTrace()
+}
+
+// innerStack returns the stack of the
+// inner-most Error in err's chain.
+func innerStack(err error) *stack {
+	var stack *stack
+	Walk(err, func(err error) bool {
+		if e, ok := asError(err); ok && e.stack != nil {
+			stack = e.stack
+		}
+		return true
+	})
+	return stack
+}
+
+// hasStack reports whether an Error in err's
+// chain has a stack, stopping at the first.
+func hasStack(err error) bool {
+	_, ok := findError(err, func(e Error) bool { return e.stack != nil })
+	return ok
+}
+
+// Frame represents a program counter inside a stack frame.
+type Frame uintptr
+
+// pc returns the program counter for this frame;
+// multiple frames may have the same PC value.
+func (f Frame) pc() uintptr { return uintptr(f) - 1 }
+
+// file returns the full path to the file that contains the
+// function for this Frame's pc.
+func (f Frame) file() string {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return "unknown"
+	}
+	file, _ := fn.FileLine(f.pc())
+	return file
+}
+
+// line returns the line number of source code of the
+// function for this Frame's pc.
+func (f Frame) line() int {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return 0
+	}
+	_, line := fn.FileLine(f.pc())
+	return line
+}
+
+// Format formats the frame according to the fmt.Formatter interface.
+//
+//    %s    source file
+//    %d    source line
+//    %n    function name
+//    %v    equivalent to %s:%d
+//
+// Format accepts flags that alter the printing of some verbs, as follows:
+//
+//    %+s   function name and path of source file relative to the compile time
+//          GOPATH separated by \n\t (<funcname>\n\t<path>)
+//    %+v   equivalent to %+s:%d
+func (f Frame) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		switch {
+		case s.Flag('+'):
+			pc := f.pc()
+			fn := runtime.FuncForPC(pc)
+			if fn == nil {
+				io.WriteString(s, "unknown")
+			} else {
+				file, _ := fn.FileLine(pc)
+				fmt.Fprintf(s, "%s\n\t%s", fn.Name(), file)
+			}
+		default:
+			io.WriteString(s, path.Base(f.file()))
+		}
+	case 'd':
+		fmt.Fprintf(s, "%d", f.line())
+	case 'n':
+		name := runtime.FuncForPC(f.pc()).Name()
+		io.WriteString(s, funcname(name))
+	case 'v':
+		f.Format(s, 's')
+		io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// funcname removes the path prefix component of a function's name reported by func.Name().
+func funcname(name string) string {
+	i := strings.LastIndex(name, "/")
+	name = name[i+1:]
+	i = strings.Index(name, ".")
+	return name[i+1:]
+}
+
+// StackMode controls when E captures a stack.
+type StackMode int
+
+const (
+	// StackInnermost captures a stack only when the
+	// wrapped error doesn't already carry one, so a
+	// chain costs a single capture.
+	StackInnermost StackMode = iota
+	// StackAlways captures a stack in every E call.
+	StackAlways
+	// StackNever doesn't capture stacks at all. Use
+	// it on hot paths where the cost isn't wanted.
+	StackNever
+)
+
+var (
+	// CaptureStack is the StackMode used by E.
+	CaptureStack = StackInnermost
+	// StackDepth is the maximum number of frames
+	// captured. Frames are only symbolized when a
+	// stack is formatted, not when it is captured.
+	StackDepth = 32
+)
+
+// stack represents a stack of program counters.
+type stack []uintptr
+
+func (s *stack) Format(st fmt.State, verb rune) {
+	if s == nil {
+		return
+	}
+	switch verb {
+	case 'v':
+		switch {
+		case st.Flag('+'):
+			for _, pc := range *s {
+				f := Frame(pc)
+				fmt.Fprintf(st, "\n%+v", f)
+			}
+		}
+	}
+}
+
+// StackTrace is stack of Frames from innermost (newest) to outermost (oldest).
+type StackTrace []Frame
+
+// Format formats the stack of Frames according to the fmt.Formatter interface.
+//
+//    %s	lists source files for each Frame in the stack
+//    %v	lists the source file and line number for each Frame in the stack
+//
+// Format accepts flags that alter the printing of some verbs, as follows:
+//
+//    %+v   Prints filename, function, and line number for each Frame in the stack.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			for _, f := range st {
+				fmt.Fprintf(s, "\n%+v", f)
+			}
+		case s.Flag('#'):
+			fmt.Fprintf(s, "%#v", []Frame(st))
+		default:
+			fmt.Fprintf(s, "%v", []Frame(st))
+		}
+	case 's':
+		fmt.Fprintf(s, "%s", []Frame(st))
+	}
+}
+
+func (s *stack) StackTrace() StackTrace {
+	if s == nil {
+		return nil
+	}
+	f := make([]Frame, len(*s))
+	for i := 0; i < len(f); i++ {
+		f[i] = Frame((*s)[i])
+	}
+	return f
+}
+
+// callers captures the stack of the caller of E or EC,
+// skipping runtime.Callers, callers, build and E or EC.
+func callers() *stack {
+	pcs := make([]uintptr, StackDepth)
+	n := runtime.Callers(4, pcs)
+	var st stack = pcs[0:n]
+	return &st
+}
@@ -0,0 +1,131 @@
+package errors_test
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/errorthrower"
+)
+
+// BenchmarkE shows the cost of capturing stacks
+// with each errors.StackMode, wrapping each level
+// of the errorthrower chain in an Error.
+func BenchmarkE(b *testing.B) {
+	defer func(mode errors.StackMode) { errors.CaptureStack = mode }(errors.CaptureStack)
+
+	modes := []struct {
+		name string
+		mode errors.StackMode
+	}{
+		{"always", errors.StackAlways},
+		{"innermost", errors.StackInnermost},
+		{"never", errors.StackNever},
+	}
+	for _, m := range modes {
+		b.Run(m.name, func(b *testing.B) {
+			errors.CaptureStack = m.mode
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := errorthrower.SomeError()
+				_ = errors.E("someError", errors.E("levelOne", errors.E("levelTwo", errors.E("levelThree", err, errors.KindBadRequest))))
+			}
+		})
+	}
+}
+
+// viaE and viaEC are small enough to be inlined into
+// their callers, as is E itself.
+func viaE() errors.Error {
+	return errors.E("", "x")
+}
+
+func viaEC() errors.Error {
+	return errors.EC(context.Background(), "", "x")
+}
+
+func TestEDerivesOp(t *testing.T) {
+	tests := []struct {
+		name string
+		err  errors.Error
+	}{
+		{"viaE", viaE()},
+		{"viaEC", viaEC()},
+	}
+	for _, tt := range tests {
+		if op := string(tt.err.Op); !strings.HasPrefix(op, tt.name+":") {
+			t.Errorf("Op = %q, want it derived from %s", op, tt.name)
+		}
+	}
+}
+
+func TestEStackStartsAtCaller(t *testing.T) {
+	st := errors.Stack(viaE())
+	if len(st) == 0 {
+		t.Fatal("no stack was captured")
+	}
+	if got := fmt.Sprintf("%n", st[0]); got != "viaE" {
+		t.Errorf("innermost frame = %q, want viaE", got)
+	}
+}
+
+func TestIsThroughE(t *testing.T) {
+	sentinel := stderrors.New("sentinel")
+	err := errors.E("service.NewOrder", errors.E("db.Insert", fmt.Errorf("insert: %w", sentinel)))
+	if !errors.Is(err, sentinel) {
+		t.Error("errors.Is didn't find the sentinel through two Errors")
+	}
+	if !stderrors.Is(err, sentinel) {
+		t.Error("the standard errors.Is didn't find the sentinel through two Errors")
+	}
+	if errors.Is(err, stderrors.New("sentinel")) {
+		t.Error("errors.Is matched a different error with the same text")
+	}
+}
+
+func TestWithField(t *testing.T) {
+	err := errors.E("service.NewOrder", "boom", errors.OrderIDField.V("o-1"))
+	with := errors.WithField(err, errors.OrderIDField.V("o-2"))
+	if got := errors.LookupString(with, errors.OrderIDField); got != "o-2" {
+		t.Errorf("order ID = %q, want o-2", got)
+	}
+	if got := errors.LookupString(err, errors.OrderIDField); got != "o-1" {
+		t.Errorf("WithField changed the original order ID to %q", got)
+	}
+	if got, want := errors.OpsText(with), "service.NewOrder"; got != want {
+		t.Errorf("OpsText = %q, want %q", got, want)
+	}
+
+	foreign := errors.WithField(stderrors.New("boom"), errors.RequestIDField.V("req-1"))
+	if got := errors.LookupString(foreign, errors.RequestIDField); got != "req-1" {
+		t.Errorf("request ID = %q, want req-1", got)
+	}
+	if ops := errors.Ops(foreign); len(ops) != 0 {
+		t.Errorf("Ops = %v, want none", ops)
+	}
+	if errors.WithField(nil, errors.OrderIDField.V("o-1")) != nil {
+		t.Error("WithField(nil) isn't nil")
+	}
+}
+
+func TestCaptureStack(t *testing.T) {
+	defer func(mode errors.StackMode) { errors.CaptureStack = mode }(errors.CaptureStack)
+
+	errors.CaptureStack = errors.StackNever
+	if st := errors.Stack(errors.E("outer", viaE())); len(st) != 0 {
+		t.Errorf("StackNever captured %d frames", len(st))
+	}
+
+	errors.CaptureStack = errors.StackInnermost
+	err := errors.E("outer", viaE())
+	st := errors.Stack(err)
+	if len(st) == 0 {
+		t.Fatal("StackInnermost captured no stack")
+	}
+	if got := fmt.Sprintf("%n", st[0]); got != "viaE" {
+		t.Errorf("innermost frame = %q, want viaE", got)
+	}
+}
@@ -0,0 +1,134 @@
+package errors
+
+import "fmt"
+
+// Field is a key under which a value can be
+// attached to an Error. Services declare their
+// own fields and attach values in E:
+//
+//	var SKU = errors.Field("sku")
+//	errors.E(op, err, SKU.V(req.SKU))
+//
+// Lookup then finds the value anywhere in the
+// chain, and Fields enumerates every value so
+// that loggers and encoders don't need to know
+// about each field.
+type Field string
+
+// Fields known by this package. C and O are
+// shorthands for attaching the first two.
+const (
+	CustomerIDField Field = "customerID"
+	OrderIDField    Field = "orderID"
+	RequestIDField  Field = "requestID"
+)
+
+// KV is a value attached to an Error under a Field.
+type KV struct {
+	Field Field
+	Value interface{}
+}
+
+// V returns a KV that E attaches
+// to the Error it constructs.
+func (f Field) V(value interface{}) KV {
+	return KV{Field: f, Value: value}
+}
+
+func (f Field) String() string {
+	return string(f)
+}
+
+// Lookup returns the first value attached
+// to f found in err's chain.
+func Lookup(err error, f Field) (interface{}, bool) {
+	var v interface{}
+	_, ok := findError(err, func(e Error) bool {
+		var ok bool
+		v, ok = e.field(f)
+		return ok
+	})
+	return v, ok
+}
+
+// LookupString is like Lookup but formats the
+// value as a string, returning "" if f is unset.
+func LookupString(err error, f Field) string {
+	v, ok := Lookup(err, f)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// Fields returns every value attached in err's
+// chain. When a Field is attached more than once
+// the outermost value wins. Fields are returned
+// in the order they were found, outermost first.
+func Fields(err error) []KV {
+	var kvs []KV
+	seen := map[Field]bool{}
+	for _, e := range errorsOf(err) {
+		if e.fields == nil {
+			continue
+		}
+		for _, kv := range *e.fields {
+			if !seen[kv.Field] {
+				seen[kv.Field] = true
+				kvs = append(kvs, kv)
+			}
+		}
+	}
+	return kvs
+}
+
+// WithField returns err with kv attached, without
+// adding a layer to its Op chain. If err is an Error
+// kv is attached to a copy of it, replacing any value
+// of its Field, otherwise err is wrapped in an Error
+// without an Op. It returns nil if err is nil.
+func WithField(err error, kv KV) error {
+	if err == nil {
+		return nil
+	}
+	e, ok := asError(err)
+	if !ok {
+		e = Error{Err: err}
+	}
+	if e.fields != nil {
+		kvs := append([]KV(nil), *e.fields...)
+		e.fields = &kvs
+	}
+	e.addField(kv)
+	return e
+}
+
+// field returns the value attached to f on e itself.
+func (e Error) field(f Field) (interface{}, bool) {
+	if e.fields == nil {
+		return nil, false
+	}
+	for _, kv := range *e.fields {
+		if kv.Field == f {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
+
+// addField attaches kv to e, replacing
+// any value already attached to its Field.
+func (e *Error) addField(kv KV) {
+	var kvs []KV
+	if e.fields != nil {
+		kvs = *e.fields
+	}
+	for i := range kvs {
+		if kvs[i].Field == kv.Field {
+			kvs[i] = kv
+			return
+		}
+	}
+	kvs = append(kvs, kv)
+	e.fields = &kvs
+}
@@ -0,0 +1,60 @@
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+// sku is declared the way a service adds a field.
+var sku = errors.Field("sku")
+
+func TestFields(t *testing.T) {
+	err := errors.E("service.NewOrder",
+		errors.E("db.Insert", "out of stock", sku.V("abc-1"), errors.O("o-1"), errors.C("42")),
+		errors.O("o-2"),
+	)
+
+	if v, ok := errors.Lookup(err, sku); !ok || v != "abc-1" {
+		t.Errorf("Lookup(sku) = %v, %v, want abc-1", v, ok)
+	}
+	if _, ok := errors.Lookup(err, errors.Field("missing")); ok {
+		t.Error("Lookup found a field that was never attached")
+	}
+	if got := errors.LookupString(err, errors.Field("missing")); got != "" {
+		t.Errorf("LookupString of a missing field = %q", got)
+	}
+
+	// the outermost value of a Field wins.
+	want := []errors.KV{
+		errors.OrderIDField.V("o-2"),
+		sku.V("abc-1"),
+		errors.CustomerIDField.V("42"),
+	}
+	if got := errors.Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %v, want %v", got, want)
+	}
+}
+
+func TestTypedAccessors(t *testing.T) {
+	err := errors.E("outer", errors.E("inner", "boom", errors.C("42"), errors.O("o-1"), errors.GM("try again")))
+	if got, want := errors.CustomerID(err), errors.C("42"); got != want {
+		t.Errorf("CustomerID = %q, want %q", got, want)
+	}
+	if got, want := errors.OrderID(err), errors.O("o-1"); got != want {
+		t.Errorf("OrderID = %q, want %q", got, want)
+	}
+	if got, want := errors.GrpcMsg(err), errors.GM("try again"); got != want {
+		t.Errorf("GrpcMsg = %q, want %q", got, want)
+	}
+	// C and O are shorthands for their Fields.
+	if got := errors.LookupString(err, errors.CustomerIDField); got != "42" {
+		t.Errorf("customerID field = %q, want 42", got)
+	}
+
+	plain := errors.E("op", "boom")
+	if errors.CustomerID(plain) != "" || errors.OrderID(plain) != "" || errors.GrpcMsg(plain) != "" {
+		t.Error("accessors returned values that were never attached")
+	}
+}
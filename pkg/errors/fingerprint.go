@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+)
+
+// Fingerprint returns a stable hash identifying
+// the kind of failure err is, so that the same
+// failure can be grouped across many requests.
+// It is built from the Op chain, the Kind, the
+// inner-most stack frame and the type of the root
+// cause. Values that vary per request, such as
+// fields and messages, are left out. The children
+// of a MultiError contribute their own fingerprints.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	h := fnv.New64a()
+	for _, op := range Ops(err) {
+		io.WriteString(h, string(op))
+		io.WriteString(h, "\n")
+	}
+	io.WriteString(h, strconv.Itoa(Kind(err)))
+	if st := innerStack(err); st != nil && len(*st) > 0 {
+		f := Frame((*st)[0])
+		fmt.Fprintf(h, "\n%n %s:%d", f, f, f)
+	}
+
+	// the type of the root cause tells apart
+	// errors that carry no Ops or stack.
+	var root error
+	Walk(err, func(err error) bool {
+		root = err
+		return true
+	})
+	fmt.Fprintf(h, "\n%T", root)
+	for _, child := range Errors(err) {
+		io.WriteString(h, "\n")
+		io.WriteString(h, Fingerprint(child))
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
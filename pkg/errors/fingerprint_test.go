@@ -0,0 +1,43 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+// orderFailure builds the same chain wherever it is
+// called from, so that its stack is the same too.
+func orderFailure(op errors.Op, kind int, customerID, msg string) error {
+	return errors.E("server.NewOrder", errors.E(op, msg, kind, errors.C(customerID)))
+}
+
+func TestFingerprintStable(t *testing.T) {
+	a := orderFailure("db.Insert", errors.KindUnavailable, "1", "connection refused")
+	b := orderFailure("db.Insert", errors.KindUnavailable, "2", "connection reset")
+	if errors.Fingerprint(a) != errors.Fingerprint(b) {
+		t.Error("the customer ID or message changed the fingerprint")
+	}
+	if errors.Fingerprint(a) != errors.Fingerprint(a) {
+		t.Error("the fingerprint of the same error changed")
+	}
+}
+
+func TestFingerprintDistinct(t *testing.T) {
+	base := orderFailure("db.Insert", errors.KindUnavailable, "1", "down")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"kind", orderFailure("db.Insert", errors.KindDeadlineExceeded, "1", "down")},
+		{"op", orderFailure("db.Select", errors.KindUnavailable, "1", "down")},
+	}
+	for _, tt := range tests {
+		if errors.Fingerprint(tt.err) == errors.Fingerprint(base) {
+			t.Errorf("a different %s gave the same fingerprint", tt.name)
+		}
+	}
+	if errors.Fingerprint(nil) != "" {
+		t.Error("Fingerprint(nil) isn't empty")
+	}
+}
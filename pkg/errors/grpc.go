@@ -0,0 +1,388 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the ErrorInfo domain attached
+// to every status created by GRPCStatus.
+var Domain = "errhandling"
+
+// StatusDebugInfo controls whether GRPCStatus
+// attaches a DebugInfo detail carrying the stack.
+// It is off by default since stacks must not reach
+// untrusted clients, enable it only for trusted or
+// debug deployments.
+var StatusDebugInfo = false
+
+// KindCodes maps each Kind to the gRPC code
+// GRPCStatus uses when an Error was not given
+// an explicit codes.Code. Services may add to or
+// override entries during initialization, before
+// any error is built, as with the other tables of
+// this package such as KindRetries and Redactions.
+var KindCodes = map[int]codes.Code{
+	KindNotFound:         codes.NotFound,
+	KindBadRequest:       codes.InvalidArgument,
+	KindUnexpected:       codes.Internal,
+	KindAlreadyExists:    codes.AlreadyExists,
+	KindRateLimit:        codes.ResourceExhausted,
+	KindNotImplemented:   codes.Unimplemented,
+	KindUnavailable:      codes.Unavailable,
+	KindDeadlineExceeded: codes.DeadlineExceeded,
+}
+
+// CodeKinds is the reverse of KindCodes. It is
+// used to give a Kind to status errors received
+// from downstream gRPC calls.
+var CodeKinds = map[codes.Code]int{
+	codes.NotFound:           KindNotFound,
+	codes.InvalidArgument:    KindBadRequest,
+	codes.OutOfRange:         KindBadRequest,
+	codes.FailedPrecondition: KindBadRequest,
+	codes.Internal:           KindUnexpected,
+	codes.Unknown:            KindUnexpected,
+	codes.DataLoss:           KindUnexpected,
+	codes.AlreadyExists:      KindAlreadyExists,
+	codes.Aborted:            KindAlreadyExists,
+	codes.ResourceExhausted:  KindRateLimit,
+	codes.Unimplemented:      KindNotImplemented,
+	codes.Unavailable:        KindUnavailable,
+	codes.DeadlineExceeded:   KindDeadlineExceeded,
+}
+
+// KindMessages holds the default client facing
+// message for each Kind. It is used when an Error
+// was not given an explicit GM so that internal
+// error text is never sent to clients.
+var KindMessages = map[int]GM{
+	KindNotFound:         "the requested resource was not found",
+	KindBadRequest:       "the request is invalid",
+	KindUnexpected:       "an unexpected error occurred",
+	KindAlreadyExists:    "the resource already exists",
+	KindRateLimit:        "too many requests, try again later",
+	KindNotImplemented:   "the operation is not implemented",
+	KindUnavailable:      "the service is unavailable, try again later",
+	KindDeadlineExceeded: "the request timed out",
+}
+
+// GRPCStatus implements the interface used by
+// the grpc status package to convert an error
+// into a *status.Status.
+func (e Error) GRPCStatus() *status.Status {
+	st := status.New(Code(e), string(StatusMsg(e)))
+	if ds, err := st.WithDetails(Details(e)...); err == nil {
+		st = ds
+	}
+	return st
+}
+
+// Details returns the google.rpc detail
+// messages that describe err: an ErrorInfo
+// always, DebugInfo when a stack was captured,
+// BadRequest/RetryInfo when they were set and
+// RequestInfo when a request ID is attached.
+// The children of a MultiError each add their
+// own ErrorInfo and their field violations.
+func Details(err error) []proto.Message {
+	details := []proto.Message{errorInfo(err)}
+	if st := innerStack(err); st != nil && StatusDebugInfo {
+		details = append(details, debugInfo(st))
+	}
+	br := BadRequest(err)
+	for _, child := range Errors(err) {
+		details = append(details, errorInfo(child))
+		br = mergeBadRequest(br, BadRequest(child))
+	}
+	if br != nil {
+		details = append(details, br)
+	}
+	if d := RetryAfter(err); d > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(d)})
+	}
+	if id := LookupString(err, RequestIDField); id != "" {
+		details = append(details, &errdetails.RequestInfo{RequestId: id})
+	}
+	return details
+}
+
+// Metadata keys of the ErrorInfo detail. Every
+// other key holds a value attached with a Field.
+const (
+	kindKey  = "kind"
+	opsKey   = "ops"
+	retryKey = "retryable"
+)
+
+func errorInfo(err error) *ErrorInfo {
+	kind := Kind(err)
+	md := map[string]string{}
+	for _, kv := range RedactedFields(err) {
+		md[string(kv.Field)] = fmt.Sprint(kv.Value)
+	}
+	md[kindKey] = strconv.Itoa(kind)
+	var ops []string
+	for _, op := range chainOps(err) {
+		ops = append(ops, string(op))
+	}
+	md[opsKey] = strings.Join(ops, ": ")
+	md[retryKey] = strconv.FormatBool(Retryable(err))
+	r := reason(kind)
+	if code := CodeOf(err); code != "" {
+		r = string(code)
+	}
+	return &ErrorInfo{
+		Reason:   r,
+		Domain:   Domain,
+		Metadata: md,
+	}
+}
+
+// reason turns a Kind into an ErrorInfo
+// reason, e.g. "Not Found" becomes NOT_FOUND.
+func reason(kind int) string {
+	return strings.ToUpper(strings.Replace(http.StatusText(kind), " ", "_", -1))
+}
+
+// mergeBadRequest returns the field
+// violations of a and b combined.
+func mergeBadRequest(a, b *errdetails.BadRequest) *errdetails.BadRequest {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	fvs := append(a.FieldViolations[:len(a.FieldViolations):len(a.FieldViolations)], b.FieldViolations...)
+	return &errdetails.BadRequest{FieldViolations: fvs}
+}
+
+func debugInfo(st *stack) *errdetails.DebugInfo {
+	entries := make([]string, 0, len(*st))
+	for _, f := range st.StackTrace() {
+		entries = append(entries, fmt.Sprintf("%+v", f))
+	}
+	return &errdetails.DebugInfo{StackEntries: entries}
+}
+
+// CodeFromKind returns the gRPC code for
+// kind, or codes.Unknown if it has none.
+func CodeFromKind(kind int) codes.Code {
+	if c, ok := KindCodes[kind]; ok {
+		return c
+	}
+	return codes.Unknown
+}
+
+// KindFromCode returns the Kind for code,
+// or KindUnexpected if it has none.
+func KindFromCode(code codes.Code) int {
+	if k, ok := CodeKinds[code]; ok {
+		return k
+	}
+	return KindUnexpected
+}
+
+// Code returns the gRPC code for err. An
+// explicit codes.Code in the chain wins,
+// followed by the code of a downstream status
+// error when no Kind was set, and finally the
+// code mapped from Kind.
+func Code(err error) codes.Code {
+	if c := GrpcCode(err); c != nil {
+		return *c
+	}
+
+	if src, ok := kindSource(err); ok {
+		if st, ok := statusOf(src); ok {
+			return st.Code()
+		}
+	}
+	return CodeFromKind(Kind(err))
+}
+
+// StatusMsg returns the client facing message
+// for err. An explicit GM in the chain wins,
+// with redacted fields removed from it, then
+// the message of its catalog ErrorCode if all
+// the fields it uses are set, and otherwise the
+// default message for its Kind.
+func StatusMsg(err error) GM {
+	if m := GrpcMsg(err); m != "" {
+		return GM(RedactText(err, string(m)))
+	}
+	if def, ok := Def(CodeOf(err)); ok {
+		if m, ok := def.render(RedactedFields(err)); ok {
+			return GM(m)
+		}
+	}
+
+	kind := Kind(err)
+	if m, ok := KindMessages[kind]; ok {
+		return m
+	}
+	return GM(http.StatusText(kind))
+}
+
+// FromError rebuilds the Error encoded into
+// a status error returned from a gRPC call. It
+// returns false if err does not carry a status
+// or already is an athens error.
+func FromError(err error) (Error, bool) {
+	if err == nil {
+		return Error{}, false
+	}
+	st, ok := statusOf(err)
+	if !ok {
+		return Error{}, false
+	}
+	return FromStatus(st), true
+}
+
+// FromStatus rebuilds the Error a remote service
+// encoded into st with GRPCStatus. The remote Op
+// chain is restored as nested Errors so that Ops
+// and OpsText report it after the local ones. The
+// children of a remote MultiError, described by any
+// further ErrorInfo, are restored as a MultiError.
+func FromStatus(st *status.Status) Error {
+	code := st.Code()
+	e := Error{
+		Kind:     KindFromCode(code),
+		Err:      errors.New(st.Message()),
+		GrpcCode: &code,
+		GrpcMsg:  GM(st.Message()),
+	}
+
+	var ops []Op
+	var seenInfo bool
+	var children []error
+	for _, a := range st.Proto().GetDetails() {
+		switch {
+		case isDetail(a, errorInfoName):
+			var info ErrorInfo
+			if proto.Unmarshal(a.Value, &info) != nil {
+				continue
+			}
+			if !seenInfo {
+				seenInfo = true
+				e, ops = fromErrorInfo(&info, e)
+				continue
+			}
+			// any further ErrorInfo describes
+			// a child of a remote MultiError.
+			child, childOps := fromErrorInfo(&info, Error{Kind: KindUnexpected})
+			child.Err = errors.New(string(StatusMsg(child)))
+			children = append(children, nest(child, childOps))
+		case isDetail(a, proto.MessageName(&errdetails.BadRequest{})):
+			br := &errdetails.BadRequest{}
+			if ptypes.UnmarshalAny(a, br) == nil {
+				e.BadRequest = br
+			}
+		case isDetail(a, proto.MessageName(&errdetails.RetryInfo{})):
+			var ri errdetails.RetryInfo
+			if ptypes.UnmarshalAny(a, &ri) == nil {
+				e.RetryAfter, _ = ptypes.Duration(ri.RetryDelay)
+			}
+		case isDetail(a, proto.MessageName(&errdetails.RequestInfo{})):
+			var ri errdetails.RequestInfo
+			if ptypes.UnmarshalAny(a, &ri) == nil && ri.RequestId != "" {
+				e.addField(RequestIDField.V(ri.RequestId))
+			}
+		}
+	}
+	if len(children) > 0 {
+		e.Err = &MultiError{Errors: children}
+	}
+
+	return nest(e, ops)
+}
+
+// fromErrorInfo sets the Kind, retryability, ErrorCode
+// and fields described by info on e, and returns it
+// with the Op chain info carries.
+func fromErrorInfo(info *ErrorInfo, e Error) (Error, []Op) {
+	var ops []Op
+	keys := make([]string, 0, len(info.Metadata))
+	for k := range info.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := info.Metadata[k]
+		switch k {
+		case kindKey:
+			if kind, err := strconv.Atoi(v); err == nil {
+				e.Kind = kind
+			}
+		case retryKey:
+			e.Retry = parseRetry(v)
+		case opsKey:
+			if v == "" {
+				continue
+			}
+			for _, op := range strings.Split(v, ": ") {
+				ops = append(ops, Op(op))
+			}
+		default:
+			e.addField(Field(k).V(v))
+		}
+	}
+
+	// a reason that isn't derived from the
+	// Kind is an ErrorCode from the catalog.
+	if info.Reason != "" && info.Reason != reason(e.Kind) {
+		e.Code = ErrorCode(info.Reason)
+	}
+	return e, ops
+}
+
+// HasErrorInfo reports whether st carries the
+// ErrorInfo of Domain, i.e. it was created by
+// GRPCStatus rather than by the gRPC runtime.
+func HasErrorInfo(st *status.Status) bool {
+	for _, a := range st.Proto().GetDetails() {
+		var info ErrorInfo
+		if isDetail(a, errorInfoName) && proto.Unmarshal(a.Value, &info) == nil && info.Domain == Domain {
+			return true
+		}
+	}
+	return false
+}
+
+func isDetail(a *any.Any, name string) bool {
+	return strings.HasSuffix(a.GetTypeUrl(), "/"+name)
+}
+
+// statusOf returns the status carried by err
+// if it is a status error from a downstream call
+// rather than an athens error.
+func statusOf(err error) (*status.Status, bool) {
+	if _, ok := asError(err); ok {
+		return nil, false
+	}
+	if _, ok := asMulti(err); ok {
+		return nil, false
+	}
+	if r, ok := err.(redactedError); ok {
+		return statusOf(r.err)
+	}
+	se, ok := err.(interface{ GRPCStatus() *status.Status })
+	if !ok {
+		return nil, false
+	}
+	st := se.GRPCStatus()
+	return st, st != nil
+}
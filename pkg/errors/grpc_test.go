@@ -0,0 +1,127 @@
+package errors_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+// overTheWire sends the status of err through
+// protobuf and back, as a gRPC client gets it.
+func overTheWire(t *testing.T, err error) error {
+	t.Helper()
+	b, merr := proto.Marshal(status.Convert(err).Proto())
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	var p spb.Status
+	if merr := proto.Unmarshal(b, &p); merr != nil {
+		t.Fatal(merr)
+	}
+	return status.FromProto(&p).Err()
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	err := errors.E("server.NewOrder",
+		errors.E("db.Insert", "duplicate key", errors.KindAlreadyExists,
+			errors.GM("order already placed"),
+			errors.O("o-42"),
+			&errdetails.BadRequest_FieldViolation{Field: "orderID", Description: "taken"},
+			2*time.Second,
+		),
+		errors.RequestIDField.V("req-1"),
+	)
+
+	remote, ok := errors.FromError(overTheWire(t, err))
+	if !ok {
+		t.Fatal("FromError didn't find a status")
+	}
+	if got, want := errors.Kind(remote), errors.KindAlreadyExists; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	if got, want := errors.Code(remote), codes.AlreadyExists; got != want {
+		t.Errorf("Code = %v, want %v", got, want)
+	}
+	if got, want := errors.OpsText(remote), "server.NewOrder: db.Insert"; got != want {
+		t.Errorf("OpsText = %q, want %q", got, want)
+	}
+	if got, want := errors.GrpcMsg(remote), errors.GM("order already placed"); got != want {
+		t.Errorf("GrpcMsg = %q, want %q", got, want)
+	}
+	if got, want := errors.OrderID(remote), errors.O("o-42"); got != want {
+		t.Errorf("OrderID = %q, want %q", got, want)
+	}
+	if got, want := errors.LookupString(remote, errors.RequestIDField), "req-1"; got != want {
+		t.Errorf("request ID = %q, want %q", got, want)
+	}
+	if got, want := errors.RetryAfter(remote), 2*time.Second; got != want {
+		t.Errorf("RetryAfter = %v, want %v", got, want)
+	}
+	br := errors.BadRequest(remote)
+	if br == nil || len(br.FieldViolations) != 1 || br.FieldViolations[0].Field != "orderID" {
+		t.Errorf("BadRequest = %v, want the orderID violation", br)
+	}
+}
+
+const testRoundTrip errors.ErrorCode = "TEST_ROUND_TRIP"
+
+func init() {
+	errors.Register(errors.CodeDef{
+		Code:    testRoundTrip,
+		Kind:    errors.KindNotFound,
+		Message: "order {{.orderID}} not found",
+	})
+}
+
+func TestStatusRoundTripCode(t *testing.T) {
+	err := errors.E("server.GetOrder", testRoundTrip, errors.O("o-7"))
+
+	remote, ok := errors.FromError(overTheWire(t, err))
+	if !ok {
+		t.Fatal("FromError didn't find a status")
+	}
+	if got, want := errors.CodeOf(remote), testRoundTrip; got != want {
+		t.Errorf("CodeOf = %q, want %q", got, want)
+	}
+	if got, want := errors.StatusMsg(remote), errors.GM("order o-7 not found"); got != want {
+		t.Errorf("StatusMsg = %q, want %q", got, want)
+	}
+}
+
+func TestStatusDebugInfo(t *testing.T) {
+	defer func(v bool) { errors.StatusDebugInfo = v }(errors.StatusDebugInfo)
+
+	err := errors.E("server.NewOrder", "boom")
+	for _, enabled := range []bool{false, true} {
+		errors.StatusDebugInfo = enabled
+		var found bool
+		for _, d := range err.GRPCStatus().Details() {
+			if _, ok := d.(*errdetails.DebugInfo); ok {
+				found = true
+			}
+		}
+		if found != enabled {
+			t.Errorf("StatusDebugInfo = %v, DebugInfo attached = %v", enabled, found)
+		}
+	}
+}
+
+func TestFromErrorPlainStatus(t *testing.T) {
+	remote, ok := errors.FromError(status.Error(codes.Unavailable, "connection refused"))
+	if !ok {
+		t.Fatal("FromError didn't find a status")
+	}
+	if got, want := errors.Kind(remote), errors.KindUnavailable; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	if _, ok := errors.FromError(errors.E("op", "local")); ok {
+		t.Error("FromError rebuilt an athens error")
+	}
+}
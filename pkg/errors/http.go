@@ -0,0 +1,208 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProblemContentType is the media type of
+// an RFC 7807 problem details document.
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase is prepended to the ErrorCode,
+// or the reason of the Kind, to form the type URI
+// of a Problem, e.g. /problems/NOT_FOUND.
+var ProblemTypeBase = "/problems/"
+
+// Problem is the RFC 7807 problem details document
+// WriteProblem writes for an error. Like a gRPC
+// status it only carries the client facing message
+// and redacted field values. Code, Ops, Fields and
+// Retryable are extension members. Retryable is a
+// pointer so that a document from another service
+// that lacks it leaves retrying to the Kind default.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	Code      string                 `json:"code,omitempty"`
+	Ops       []string               `json:"ops,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Retryable *bool                  `json:"retryable,omitempty"`
+}
+
+// NewProblem returns the Problem describing err. Its
+// instance is the request ID attached to err, if any.
+func NewProblem(err error) Problem {
+	kind := Kind(err)
+	retryable := Retryable(err)
+	p := Problem{
+		Type:      ProblemTypeBase + reason(kind),
+		Title:     http.StatusText(kind),
+		Status:    HTTPStatus(err),
+		Detail:    string(StatusMsg(err)),
+		Instance:  LookupString(err, RequestIDField),
+		Code:      CodeOf(err).String(),
+		Retryable: &retryable,
+	}
+	if p.Code != "" {
+		p.Type = ProblemTypeBase + p.Code
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	for _, op := range Ops(err) {
+		p.Ops = append(p.Ops, string(op))
+	}
+	for _, kv := range RedactedFields(err) {
+		if kv.Field == RequestIDField {
+			continue
+		}
+		if p.Fields == nil {
+			p.Fields = map[string]interface{}{}
+		}
+		p.Fields[kv.Field.String()] = kv.Value
+	}
+	return p
+}
+
+// Err rebuilds the Error described by p. The
+// remote Op chain is restored as nested Errors.
+func (p Problem) Err() Error {
+	kind := p.Status
+	if kind == 0 {
+		kind = KindUnexpected
+	}
+	e := Error{
+		Kind:    kind,
+		Err:     errors.New(p.Detail),
+		GrpcMsg: GM(p.Detail),
+	}
+	if p.Retryable != nil {
+		r := Retry(*p.Retryable)
+		e.Retry = &r
+	}
+	if p.Detail == "" {
+		e.Err = errors.New(p.Title)
+	}
+	if code := p.Code; code != "" {
+		e.Code = ErrorCode(code)
+	} else if t := strings.TrimPrefix(p.Type, ProblemTypeBase); t != p.Type && t != reason(kind) {
+		e.Code = ErrorCode(t)
+	}
+	keys := make([]string, 0, len(p.Fields))
+	for k := range p.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		e.addField(Field(k).V(p.Fields[k]))
+	}
+	if p.Instance != "" {
+		e.addField(RequestIDField.V(p.Instance))
+	}
+	return nest(e, ops(p.Ops))
+}
+
+// DecodeProblem parses the problem details
+// document in r back into an Error.
+func DecodeProblem(r io.Reader) (Error, error) {
+	var p Problem
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return Error{}, err
+	}
+	return p.Err(), nil
+}
+
+// HTTPStatus returns the HTTP status code for err,
+// which is its Kind since Kinds are HTTP statuses.
+// Kinds that aren't error statuses, such as
+// KindRedirect, become 500 so that clients never
+// take a failed call for a success or redirect.
+func HTTPStatus(err error) int {
+	kind := Kind(err)
+	if kind < 400 || kind > 599 {
+		return http.StatusInternalServerError
+	}
+	return kind
+}
+
+// WriteProblem writes err to w as a Problem with the
+// status from HTTPStatus. A RetryAfter in err's chain
+// is sent in the Retry-After header.
+func WriteProblem(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	setRetryAfter(w.Header(), err)
+	w.WriteHeader(HTTPStatus(err))
+	json.NewEncoder(w).Encode(NewProblem(err))
+}
+
+func setRetryAfter(h http.Header, err error) {
+	if d := RetryAfter(err); d > 0 {
+		h.Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+	}
+}
+
+// FromHTTPResponse rebuilds the Error a remote service
+// wrote to resp with WriteProblem. A body that isn't a
+// Problem gives an Error of the response's status. It
+// returns false if resp is not an error response, that
+// is its status is below 400 and its body isn't a
+// Problem. The body is read but not closed.
+func FromHTTPResponse(resp *http.Response) (Error, bool) {
+	mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode < 400 && mt != ProblemContentType {
+		return Error{}, false
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	var p Problem
+	if json.Unmarshal(body, &p) != nil || p.Status == 0 {
+		status := resp.StatusCode
+		if status < 400 {
+			status = http.StatusInternalServerError
+		}
+		p = Problem{
+			Title:  http.StatusText(status),
+			Status: status,
+		}
+	}
+
+	e := p.Err()
+	if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		e.RetryAfter = time.Duration(s) * time.Second
+	}
+	return e, true
+}
+
+func ops(ss []string) []Op {
+	ops := make([]Op, len(ss))
+	for i, s := range ss {
+		ops[i] = Op(s)
+	}
+	return ops
+}
+
+// nest gives e the innermost of ops and wraps it
+// in an Error for each of the other ops in turn.
+func nest(e Error, ops []Op) Error {
+	if len(ops) == 0 {
+		return e
+	}
+	e.Op = ops[len(ops)-1]
+	for i := len(ops) - 2; i >= 0; i-- {
+		e = Error{Op: ops[i], Err: e}
+	}
+	return e
+}
@@ -0,0 +1,150 @@
+package errors_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func TestProblemRoundTrip(t *testing.T) {
+	err := errors.E("server.NewOrder",
+		errors.E("db.Insert", "too many orders", errors.KindRateLimit, errors.O("o-1"), 3*time.Second),
+		errors.RequestIDField.V("req-1"),
+	)
+
+	w := httptest.NewRecorder()
+	errors.WriteProblem(w, err)
+	resp := w.Result()
+
+	if got := resp.Header.Get("Content-Type"); got != errors.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", got, errors.ProblemContentType)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "3" {
+		t.Errorf("Retry-After = %q, want 3", got)
+	}
+
+	remote, ok := errors.FromHTTPResponse(resp)
+	if !ok {
+		t.Fatal("FromHTTPResponse didn't see an error response")
+	}
+	if got, want := errors.Kind(remote), errors.KindRateLimit; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	if got, want := errors.OpsText(remote), "server.NewOrder: db.Insert"; got != want {
+		t.Errorf("OpsText = %q, want %q", got, want)
+	}
+	if got, want := errors.OrderID(remote), errors.O("o-1"); got != want {
+		t.Errorf("OrderID = %q, want %q", got, want)
+	}
+	if got, want := errors.LookupString(remote, errors.RequestIDField), "req-1"; got != want {
+		t.Errorf("request ID = %q, want %q", got, want)
+	}
+	if got, want := errors.RetryAfter(remote), 3*time.Second; got != want {
+		t.Errorf("RetryAfter = %v, want %v", got, want)
+	}
+	if !errors.Retryable(remote) {
+		t.Error("Retryable = false, want true")
+	}
+}
+
+func TestDecodeProblem(t *testing.T) {
+	body := `{"type":"/problems/ORDERS_NOT_FOUND","title":"Not Found","status":404,"detail":"order o-9 not found","ops":["a","b"],"fields":{"orderID":"o-9"},"retryable":false}`
+	e, err := errors.DecodeProblem(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := errors.Kind(e), errors.KindNotFound; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	if got, want := errors.CodeOf(e), errors.ErrorCode("ORDERS_NOT_FOUND"); got != want {
+		t.Errorf("CodeOf = %q, want %q", got, want)
+	}
+	if got, want := errors.StatusMsg(e), errors.GM("order o-9 not found"); got != want {
+		t.Errorf("StatusMsg = %q, want %q", got, want)
+	}
+	if got, want := errors.OpsText(e), "a: b"; got != want {
+		t.Errorf("OpsText = %q, want %q", got, want)
+	}
+
+	if _, err := errors.DecodeProblem(strings.NewReader("not json")); err == nil {
+		t.Error("DecodeProblem accepted a body that isn't JSON")
+	}
+}
+
+func TestDecodeProblemWithoutRetryable(t *testing.T) {
+	body := `{"type":"about:blank","title":"Service Unavailable","status":503}`
+	e, err := errors.DecodeProblem(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Retryable(e) {
+		t.Error("Retryable = false, want the default of KindUnavailable")
+	}
+
+	body = `{"type":"about:blank","title":"Service Unavailable","status":503,"retryable":false}`
+	if e, err = errors.DecodeProblem(strings.NewReader(body)); err != nil {
+		t.Fatal(err)
+	}
+	if errors.Retryable(e) {
+		t.Error("Retryable = true, want the explicit false")
+	}
+}
+
+func TestFromHTTPResponseNotProblem(t *testing.T) {
+	w := httptest.NewRecorder()
+	http.Error(w, "upstream is down", http.StatusServiceUnavailable)
+
+	remote, ok := errors.FromHTTPResponse(w.Result())
+	if !ok {
+		t.Fatal("FromHTTPResponse didn't see an error response")
+	}
+	if got, want := errors.Kind(remote), errors.KindUnavailable; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+	if !errors.Retryable(remote) {
+		t.Error("Retryable = false, want true")
+	}
+
+	ok200 := httptest.NewRecorder()
+	ok200.WriteHeader(http.StatusOK)
+	if _, ok := errors.FromHTTPResponse(ok200.Result()); ok {
+		t.Error("FromHTTPResponse treated a 200 as an error")
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		kind int
+		want int
+	}{
+		{errors.KindNotFound, http.StatusNotFound},
+		{errors.KindUnavailable, http.StatusServiceUnavailable},
+		{errors.KindRedirect, http.StatusInternalServerError},
+		{http.StatusOK, http.StatusInternalServerError},
+		{42, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := errors.HTTPStatus(errors.E("op", "failed", tt.kind)); got != tt.want {
+			t.Errorf("HTTPStatus(Kind %d) = %d, want %d", tt.kind, got, tt.want)
+		}
+	}
+}
+
+func TestFromHTTPResponseProblemBelow400(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", errors.ProblemContentType+"; charset=utf-8")
+	w.WriteHeader(http.StatusMovedPermanently)
+	w.WriteString(`{"type":"/problems/NOT_FOUND","title":"Not Found","status":404,"detail":"order o-9 not found"}`)
+
+	remote, ok := errors.FromHTTPResponse(w.Result())
+	if !ok {
+		t.Fatal("FromHTTPResponse treated a problem as a success")
+	}
+	if got, want := errors.Kind(remote), errors.KindNotFound; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"text/template"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultLocale is the locale of GM, the catalog
+// and KindMessages. It is used when a request
+// doesn't ask for a locale that has messages.
+var DefaultLocale = "en"
+
+// LocaleKey is the gRPC metadata key
+// the locale of a request is read from.
+const LocaleKey = "accept-language"
+
+var messages = struct {
+	sync.RWMutex
+	locales map[string]map[string]*template.Template
+}{locales: map[string]map[string]*template.Template{}}
+
+// RegisterMessages adds the messages of a locale,
+// e.g. "fr" or "pt-BR". Each message is keyed by an
+// ErrorCode or by the ErrorInfo reason of a Kind,
+// e.g. NOT_FOUND, and is a text/template rendered
+// with the error's redacted fields, and is skipped
+// for an error that lacks a field it uses. It panics
+// if a template doesn't parse.
+func RegisterMessages(locale string, msgs map[string]string) {
+	messages.Lock()
+	defer messages.Unlock()
+	locale = strings.ToLower(locale)
+	if messages.locales[locale] == nil {
+		messages.locales[locale] = map[string]*template.Template{}
+	}
+	for key, msg := range msgs {
+		messages.locales[locale][key] = template.Must(template.New(key).Option("missingkey=error").Parse(msg))
+	}
+}
+
+// Locale returns the first locale of the accept-language
+// metadata of ctx that has registered messages, trying
+// each tag before its base language, or DefaultLocale.
+func Locale(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	messages.RLock()
+	defer messages.RUnlock()
+	for _, v := range md.Get(LocaleKey) {
+		for _, tag := range strings.Split(v, ",") {
+			tag = strings.ToLower(strings.TrimSpace(strings.SplitN(tag, ";", 2)[0]))
+			if _, ok := messages.locales[tag]; ok {
+				return tag
+			}
+			base := strings.SplitN(tag, "-", 2)[0]
+			if _, ok := messages.locales[base]; ok {
+				return base
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// LocalizedMsg returns the client facing message for err
+// in locale. A message registered for its ErrorCode wins,
+// then an explicit GM, then a message registered for its
+// Kind, and otherwise StatusMsg in DefaultLocale.
+func LocalizedMsg(err error, locale string) (GM, string) {
+	if m, ok := localized(err, locale, string(CodeOf(err))); ok {
+		return m, locale
+	}
+	if GrpcMsg(err) == "" {
+		if m, ok := localized(err, locale, reason(Kind(err))); ok {
+			return m, locale
+		}
+	}
+	return StatusMsg(err), DefaultLocale
+}
+
+func localized(err error, locale, key string) (GM, bool) {
+	if key == "" {
+		return "", false
+	}
+	messages.RLock()
+	tmpl, ok := messages.locales[strings.ToLower(locale)][key]
+	messages.RUnlock()
+	if !ok {
+		return "", false
+	}
+	data := map[string]interface{}{}
+	for _, kv := range RedactedFields(err) {
+		data[kv.Field.String()] = kv.Value
+	}
+	var b strings.Builder
+	if tmpl.Execute(&b, data) != nil {
+		return "", false
+	}
+	return GM(b.String()), true
+}
+
+// LocalizedStatus is like GRPCStatus but its message is
+// in locale, which a LocalizedMessage detail also carries.
+// Internal error text such as Error() stays in English.
+func LocalizedStatus(err error, locale string) *status.Status {
+	msg, locale := LocalizedMsg(err, locale)
+	st := status.New(Code(err), string(msg))
+	details := append(Details(err), &errdetails.LocalizedMessage{Locale: locale, Message: string(msg)})
+	if ds, err := st.WithDetails(details...); err == nil {
+		st = ds
+	}
+	return st
+}
+
+// LocaleUnaryServerInterceptor turns the athens errors
+// returned by handlers into status errors localized to
+// the locale of each request. It must be the outermost
+// interceptor so that the ones it wraps still get the
+// athens errors.
+func LocaleUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if _, ok := statusOf(err); ok {
+			return resp, err
+		}
+		return resp, LocalizedStatus(err, Locale(ctx)).Err()
+	}
+}
@@ -0,0 +1,121 @@
+package errors_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func init() {
+	errors.RegisterMessages("es", map[string]string{
+		string(testOrderRejected): "el pedido {{.orderID}} fue rechazado",
+	})
+}
+
+func TestLocalizedMsg(t *testing.T) {
+	err := errors.E("server.NewOrder", testOrderRejected, errors.O("o-1"))
+	msg, locale := errors.LocalizedMsg(err, "es")
+	if got, want := msg, errors.GM("el pedido o-1 fue rechazado"); got != want {
+		t.Errorf("LocalizedMsg = %q, want %q", got, want)
+	}
+	if locale != "es" {
+		t.Errorf("locale = %q, want es", locale)
+	}
+}
+
+func TestLocalizedMsgMissingField(t *testing.T) {
+	err := errors.E("server.NewOrder", testOrderRejected)
+	msg, locale := errors.LocalizedMsg(err, "es")
+	if got, want := msg, errors.KindMessages[errors.KindBadRequest]; got != want {
+		t.Errorf("LocalizedMsg = %q, want %q", got, want)
+	}
+	if locale != errors.DefaultLocale {
+		t.Errorf("locale = %q, want %q", locale, errors.DefaultLocale)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"es", "es"},
+		{"ES-mx", "es"},
+		{"de-DE, es;q=0.8", "es"},
+		{"de", errors.DefaultLocale},
+		{"", errors.DefaultLocale},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(errors.LocaleKey, tt.header))
+		}
+		if got := errors.Locale(ctx); got != tt.want {
+			t.Errorf("Locale(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleUnaryServerInterceptor(t *testing.T) {
+	interceptor := errors.LocaleUnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.Orders/NewOrder"}
+	call := func(header string, err error) *status.Status {
+		t.Helper()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(errors.LocaleKey, header))
+		_, err = interceptor(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+			return nil, err
+		})
+		st, ok := status.FromError(err)
+		if !ok {
+			t.Fatalf("interceptor returned %v, want a status error", err)
+		}
+		return st
+	}
+	localized := func(st *status.Status) string {
+		for _, d := range st.Details() {
+			if m, ok := d.(*errdetails.LocalizedMessage); ok {
+				return m.Locale
+			}
+		}
+		return ""
+	}
+
+	err := errors.E("server.NewOrder", testOrderRejected, errors.O("o-1"))
+	st := call("es-MX, en;q=0.5", err)
+	if got, want := st.Message(), "el pedido o-1 fue rechazado"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+	if got := localized(st); got != "es" {
+		t.Errorf("LocalizedMessage locale = %q, want es", got)
+	}
+	if got, want := st.Code(), codes.InvalidArgument; got != want {
+		t.Errorf("code = %v, want %v", got, want)
+	}
+
+	st = call("de", err)
+	if got, want := st.Message(), "order o-1 was rejected"; got != want {
+		t.Errorf("fallback message = %q, want %q", got, want)
+	}
+	if got := localized(st); got != errors.DefaultLocale {
+		t.Errorf("fallback LocalizedMessage locale = %q, want %q", got, errors.DefaultLocale)
+	}
+
+	downstream := status.Error(codes.Unavailable, "connection refused")
+	if st := call("es", downstream); st.Message() != "connection refused" {
+		t.Errorf("a status error was changed to %q", st.Message())
+	}
+
+	resp, nilErr := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if resp != "ok" || nilErr != nil {
+		t.Errorf("successful call returned %v, %v", resp, nilErr)
+	}
+}
@@ -0,0 +1,207 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-kit/kit/log/level"
+	"google.golang.org/grpc/status"
+)
+
+// KindPolicy decides the Kind of a MultiError
+// from the Kinds of its children.
+type KindPolicy func(kinds []int) int
+
+// DefaultKindPolicy is used by MultiErrors
+// that don't set their own Policy.
+var DefaultKindPolicy KindPolicy = UniformKind
+
+// UniformKind returns the Kind shared by all
+// children, e.g. all BadRequest is BadRequest.
+// Mixed Kinds are KindUnexpected.
+func UniformKind(kinds []int) int {
+	if len(kinds) == 0 {
+		return KindUnexpected
+	}
+	for _, k := range kinds[1:] {
+		if k != kinds[0] {
+			return KindUnexpected
+		}
+	}
+	return kinds[0]
+}
+
+// LastKind returns the Kind of the last child,
+// e.g. the final attempt of a retried call.
+func LastKind(kinds []int) int {
+	if len(kinds) == 0 {
+		return KindUnexpected
+	}
+	return kinds[len(kinds)-1]
+}
+
+// MostSevereKind returns the child Kind with
+// the most severe KindSeverity. Ties go to the
+// higher status code, so 5xx beats 4xx.
+func MostSevereKind(kinds []int) int {
+	if len(kinds) == 0 {
+		return KindUnexpected
+	}
+	kind := kinds[0]
+	for _, k := range kinds[1:] {
+		c := CompareSeverity(KindSeverity(k), KindSeverity(kind))
+		if c > 0 || c == 0 && k > kind {
+			kind = k
+		}
+	}
+	return kind
+}
+
+// MultiError collects several errors returned
+// at once, such as from validating each line of
+// an order. Kind, Ops, Severity, GRPCStatus and
+// Format all take every child into account. It
+// is not comparable, so it is used through a
+// pointer: Multi returns one and E takes the
+// address of a MultiError value passed to it.
+type MultiError struct {
+	Op     Op
+	Errors []error
+	// Policy resolves the Kind of the MultiError,
+	// DefaultKindPolicy is used when it is nil.
+	Policy KindPolicy
+}
+
+// Multi returns a MultiError holding the non-nil
+// errs, or nil if there are none, so that it can
+// be returned directly from batch operations.
+func Multi(op Op, errs ...error) error {
+	var m MultiError
+	for _, err := range errs {
+		if err != nil {
+			m.Errors = append(m.Errors, err)
+		}
+	}
+	if len(m.Errors) == 0 {
+		return nil
+	}
+	m.Op = op
+	return &m
+}
+
+func (m MultiError) Error() string {
+	msgs := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		msgs[i] = err.Error()
+	}
+	return m.message(msgs)
+}
+
+// redacted is like Error but formats each child
+// so that Error's redaction policy is applied.
+func (m MultiError) redacted() string {
+	msgs := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		msgs[i] = fmt.Sprintf("%v", err)
+	}
+	return m.message(msgs)
+}
+
+func (m MultiError) message(msgs []string) string {
+	return fmt.Sprintf("%d errors occurred: %s", len(m.Errors), strings.Join(msgs, "; "))
+}
+
+// Format prints every child with its stack when `%+v` is used as a formatting verb
+func (m MultiError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%d errors occurred:", len(m.Errors))
+			for _, err := range m.Errors {
+				fmt.Fprintf(s, "\n* %+v", err)
+			}
+			return
+		}
+		fallthrough
+	case 's':
+		io.WriteString(s, m.redacted())
+	case 'q':
+		fmt.Fprintf(s, "%q", m.redacted())
+	}
+}
+
+// GRPCStatus implements the interface used by
+// the grpc status package to convert an error
+// into a *status.Status.
+func (m MultiError) GRPCStatus() *status.Status {
+	st := status.New(Code(m), string(StatusMsg(m)))
+	if ds, err := st.WithDetails(Details(m)...); err == nil {
+		st = ds
+	}
+	return st
+}
+
+func (m MultiError) kind() int {
+	kinds := make([]int, len(m.Errors))
+	for i, err := range m.Errors {
+		kinds[i] = Kind(err)
+	}
+	policy := m.Policy
+	if policy == nil {
+		policy = DefaultKindPolicy
+	}
+	return policy(kinds)
+}
+
+// severity returns the most severe child severity,
+// or the default for its Kind if it has no children.
+func (m MultiError) severity() level.Value {
+	var max level.Value
+	for _, err := range m.Errors {
+		if v := Severity(err); CompareSeverity(v, max) > 0 {
+			max = v
+		}
+	}
+	if max == nil {
+		return KindSeverity(m.kind())
+	}
+	return max
+}
+
+// Errors lists the children of the first
+// MultiError in err's chain, flattening any
+// nested MultiErrors. It returns nil if there
+// is no MultiError in the chain.
+func Errors(err error) []error {
+	src, ok := Find(err, func(err error) bool {
+		_, ok := asMulti(err)
+		return ok
+	})
+	if !ok {
+		return nil
+	}
+
+	m, _ := asMulti(src)
+	var errs []error
+	for _, child := range m.Errors {
+		if _, ok := asMulti(child); ok {
+			errs = append(errs, Errors(child)...)
+		} else {
+			errs = append(errs, child)
+		}
+	}
+	return errs
+}
+
+func asMulti(err error) (MultiError, bool) {
+	switch m := err.(type) {
+	case MultiError:
+		return m, true
+	case *MultiError:
+		if m != nil {
+			return *m, true
+		}
+	}
+	return MultiError{}, false
+}
@@ -0,0 +1,115 @@
+package errors_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func TestMultiOps(t *testing.T) {
+	err := errors.E("order.Validate", errors.Multi("batch",
+		errors.E("line1", errors.E("sku.Check", "unknown sku", errors.KindBadRequest)),
+		errors.E("line2", "bad quantity", errors.KindBadRequest),
+	))
+
+	want := []errors.Op{"order.Validate", "batch", "line1", "sku.Check", "line2"}
+	if got := errors.Ops(err); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Ops = %v, want %v", got, want)
+	}
+}
+
+func TestMultiKindPolicy(t *testing.T) {
+	bad := errors.E("line1", "bad", errors.KindBadRequest)
+	missing := errors.E("line2", "missing", errors.KindNotFound)
+	down := errors.E("line3", "down", errors.KindUnavailable)
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"uniform", errors.Multi("batch", bad, bad), errors.KindBadRequest},
+		{"mixed", errors.Multi("batch", bad, missing), errors.KindUnexpected},
+		{"most severe", &errors.MultiError{Errors: []error{bad, down, missing}, Policy: errors.MostSevereKind}, errors.KindUnavailable},
+		{"last", &errors.MultiError{Errors: []error{down, bad}, Policy: errors.LastKind}, errors.KindBadRequest},
+	}
+	for _, tt := range tests {
+		if got := errors.Kind(tt.err); got != tt.want {
+			t.Errorf("%s: Kind = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMultiComparable(t *testing.T) {
+	a := errors.E("op", errors.MultiError{Errors: []error{errors.E("line1", "bad")}})
+	b := errors.E("op", errors.MultiError{Errors: []error{errors.E("line1", "bad")}})
+	if errors.Is(a, b) {
+		t.Error("Is matched two different MultiErrors")
+	}
+	if !errors.Is(a, a) {
+		t.Error("Is didn't match a MultiError with itself")
+	}
+}
+
+func TestMultiSeverity(t *testing.T) {
+	if got := errors.Severity(errors.MultiError{}); got == nil {
+		t.Fatal("Severity of an empty MultiError is nil")
+	}
+	err := errors.Multi("batch",
+		errors.E("line1", "bad", errors.KindBadRequest),
+		errors.E("line2", "down", level.WarnValue()),
+	)
+	if got := errors.Severity(err); got != level.WarnValue() {
+		t.Errorf("Severity = %v, want warn", got)
+	}
+}
+
+func TestMultiNil(t *testing.T) {
+	if err := errors.Multi("batch", nil, nil); err != nil {
+		t.Fatalf("Multi of nil errors = %v, want nil", err)
+	}
+}
+
+func TestMultiStatusRoundTrip(t *testing.T) {
+	err := errors.E("order.Validate", errors.Multi("batch",
+		errors.E("line1", errors.E("sku.Check", "unknown sku", errors.KindBadRequest)),
+		errors.E("line2", "order o-1 not found", errors.KindNotFound, errors.O("o-1")),
+	))
+
+	remote, ok := errors.FromError(overTheWire(t, err))
+	if !ok {
+		t.Fatal("FromError didn't find a status")
+	}
+	if got, want := errors.Ops(remote), errors.Ops(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ops = %v, want %v", got, want)
+	}
+	if got, want := errors.Kind(remote), errors.KindUnexpected; got != want {
+		t.Errorf("Kind = %d, want %d", got, want)
+	}
+
+	children := errors.Errors(remote)
+	if len(children) != 2 {
+		t.Fatalf("%d children, want 2", len(children))
+	}
+	tests := []struct {
+		kind int
+		ops  []errors.Op
+	}{
+		{errors.KindBadRequest, []errors.Op{"line1", "sku.Check"}},
+		{errors.KindNotFound, []errors.Op{"line2"}},
+	}
+	for i, tt := range tests {
+		if got := errors.Kind(children[i]); got != tt.kind {
+			t.Errorf("child %d Kind = %d, want %d", i, got, tt.kind)
+		}
+		if got := errors.Ops(children[i]); !reflect.DeepEqual(got, tt.ops) {
+			t.Errorf("child %d Ops = %v, want %v", i, got, tt.ops)
+		}
+	}
+	if got, want := errors.OrderID(children[1]), errors.O("o-1"); got != want {
+		t.Errorf("OrderID of line2 = %q, want %q", got, want)
+	}
+}
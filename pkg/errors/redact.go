@@ -0,0 +1,211 @@
+package errors
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/status"
+)
+
+// Redaction says how the value of a Field
+// is treated before it leaves the process.
+type Redaction int
+
+const (
+	// RedactNone keeps the value as is.
+	RedactNone Redaction = iota
+	// RedactMask hides all but the last
+	// four characters of the value.
+	RedactMask
+	// RedactHash replaces the value with an HMAC
+	// keyed by HashKey so that it can still be
+	// correlated but not brute forced.
+	RedactHash
+	// RedactDrop removes the value entirely.
+	RedactDrop
+)
+
+// Redactions is the redaction policy applied
+// to fields by Format, RedactedFields, status
+// details and reports. Fields that are missing
+// are kept as is. See KindCodes for when it
+// may be changed.
+var Redactions = map[Field]Redaction{
+	CustomerIDField: RedactHash,
+}
+
+// HashKeyEnv names the environment variable
+// HashKey is read from when the program starts.
+const HashKeyEnv = "ERRORS_HASH_KEY"
+
+// HashKey is the secret RedactHash values are keyed
+// with. It is read from HashKeyEnv so that hashes
+// match across instances and restarts, and defaults
+// to a random key, so hashes only correlate within
+// one process.
+var HashKey = hashKey()
+
+func hashKey() []byte {
+	if key := os.Getenv(HashKeyEnv); key != "" {
+		return []byte(key)
+	}
+	key := make([]byte, 32)
+	rand.Read(key)
+	return key
+}
+
+// Redact applies the policy for f to v. It
+// returns false if the value must be dropped.
+func Redact(f Field, v interface{}) (interface{}, bool) {
+	switch Redactions[f] {
+	case RedactMask:
+		return mask(fmt.Sprint(v)), true
+	case RedactHash:
+		return hash(fmt.Sprint(v)), true
+	case RedactDrop:
+		return nil, false
+	}
+	return v, true
+}
+
+// RedactedFields is like Fields but applies
+// the redaction policy to every value. Fields
+// returns the unredacted values and must only
+// be used for trusted sinks.
+func RedactedFields(err error) []KV {
+	var kvs []KV
+	for _, kv := range Fields(err) {
+		if v, ok := Redact(kv.Field, kv.Value); ok {
+			kvs = append(kvs, kv.Field.V(v))
+		}
+	}
+	return kvs
+}
+
+// RedactText replaces every value in s that is
+// attached to err with a Field that has a policy
+// with its redacted form, so that messages don't
+// leak what the fields hide. Values overridden
+// further up the chain are replaced as well. Only
+// whole tokens are replaced, so a value such as
+// "1" doesn't touch "10:15".
+func RedactText(err error, s string) string {
+	var kvs []KV
+	for _, e := range errorsOf(err) {
+		if e.fields != nil {
+			kvs = append(kvs, *e.fields...)
+		}
+	}
+	for _, kv := range kvs {
+		if Redactions[kv.Field] == RedactNone {
+			continue
+		}
+		raw := fmt.Sprint(kv.Value)
+		if raw == "" {
+			continue
+		}
+		v, ok := Redact(kv.Field, kv.Value)
+		if !ok {
+			v = "[" + kv.Field.String() + "]"
+		}
+		s = replaceToken(s, raw, fmt.Sprint(v))
+	}
+	return s
+}
+
+// replaceToken replaces each occurrence of old in s
+// that isn't part of a longer word or number.
+func replaceToken(s, old, new string) string {
+	first, _ := utf8.DecodeRuneInString(old)
+	last, _ := utf8.DecodeLastRuneInString(old)
+	var b strings.Builder
+	for {
+		i := strings.Index(s, old)
+		if i < 0 {
+			break
+		}
+		end := i + len(old)
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		b.WriteString(s[:i])
+		if isWord(first) && isWord(before) || isWord(last) && isWord(after) {
+			b.WriteString(old)
+		} else {
+			b.WriteString(new)
+		}
+		s = s[end:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Redacted returns err wrapped so that its Error text
+// goes through RedactText. The chain is unchanged, so
+// Kind, Ops and the other accessors still see err. Use
+// it before handing errors to loggers that call Error,
+// such as grpc_zap.
+func Redacted(err error) error {
+	if err == nil {
+		return nil
+	}
+	return redactedError{err}
+}
+
+type redactedError struct {
+	err error
+}
+
+func (e redactedError) Error() string {
+	return RedactText(e.err, e.err.Error())
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
+
+// Format defers to err, which Error
+// and MultiError already redact.
+func (e redactedError) Format(s fmt.State, verb rune) {
+	if f, ok := e.err.(fmt.Formatter); ok {
+		f.Format(s, verb)
+		return
+	}
+	if verb == 'q' {
+		fmt.Fprintf(s, "%q", e.Error())
+		return
+	}
+	io.WriteString(s, e.Error())
+}
+
+// GRPCStatus is the status of err, so that
+// wrapping doesn't change what clients get.
+func (e redactedError) GRPCStatus() *status.Status {
+	return status.Convert(e.err)
+}
+
+func mask(s string) string {
+	const keep = 4
+	r := []rune(s)
+	if len(r) <= keep {
+		return strings.Repeat("*", len(r))
+	}
+	return strings.Repeat("*", len(r)-keep) + string(r[len(r)-keep:])
+}
+
+func hash(s string) string {
+	mac := hmac.New(sha256.New, HashKey)
+	mac.Write([]byte(s))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
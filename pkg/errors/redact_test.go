@@ -0,0 +1,97 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func TestRedactTextWholeTokens(t *testing.T) {
+	err := errors.E("op", "order 1 for customer 1 failed at 10:15", errors.C("1"))
+	got := errors.RedactText(err, err.Error())
+
+	v, _ := errors.Redact(errors.CustomerIDField, "1")
+	want := fmt.Sprintf("order %s for customer %s failed at 10:15", v, v)
+	if got != want {
+		t.Fatalf("RedactText = %q, want %q", got, want)
+	}
+}
+
+func TestRedactTextOverriddenValue(t *testing.T) {
+	inner := errors.E("inner", "customer abc not found", errors.C("abc"))
+	err := errors.E("outer", inner, errors.C("xyz"))
+	if got := fmt.Sprint(err); strings.Contains(got, "abc") {
+		t.Fatalf("formatted error %q leaks the overridden customer ID", got)
+	}
+}
+
+func TestRedactHashKeyed(t *testing.T) {
+	defer func(key []byte) { errors.HashKey = key }(errors.HashKey)
+
+	errors.HashKey = []byte("key one")
+	a, _ := errors.Redact(errors.CustomerIDField, "12345")
+	again, _ := errors.Redact(errors.CustomerIDField, "12345")
+	errors.HashKey = []byte("key two")
+	b, _ := errors.Redact(errors.CustomerIDField, "12345")
+
+	if a != again {
+		t.Errorf("hash isn't stable: %v != %v", a, again)
+	}
+	if a == b {
+		t.Error("hash doesn't depend on HashKey")
+	}
+	if strings.Contains(fmt.Sprint(a), "12345") {
+		t.Errorf("hash %v contains the value", a)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	err := errors.E("op", "customer 12345 not found", errors.C("12345"), errors.KindNotFound)
+	r := errors.Redacted(err)
+
+	if strings.Contains(r.Error(), "12345") {
+		t.Errorf("Error() = %q leaks the customer ID", r.Error())
+	}
+	if got := errors.Kind(r); got != errors.KindNotFound {
+		t.Errorf("Kind = %d, want %d", got, errors.KindNotFound)
+	}
+	if got := status.Code(r); got != codes.NotFound {
+		t.Errorf("status code = %v, want %v", got, codes.NotFound)
+	}
+	if _, ok := errors.FromError(r); ok {
+		t.Error("FromError treated a redacted athens error as a status error")
+	}
+	if errors.Redacted(nil) != nil {
+		t.Error("Redacted(nil) isn't nil")
+	}
+}
+
+func TestRedactMaskRunes(t *testing.T) {
+	const name = errors.Field("testName")
+	errors.Redactions[name] = errors.RedactMask
+	defer delete(errors.Redactions, name)
+
+	tests := []struct {
+		value, want string
+	}{
+		{"Zoë Ångström", "********tröm"},
+		{"Zoë", "***"},
+		{"12345", "*2345"},
+	}
+	for _, tt := range tests {
+		v, _ := errors.Redact(name, tt.value)
+		got := fmt.Sprint(v)
+		if got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("Redact(%q) = %q isn't valid UTF-8", tt.value, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// ErrQueueFull is returned by Async when a
+	// Report is dropped because its queue is full.
+	ErrQueueFull = errors.New("report: queue is full")
+	// ErrClosed is returned by Async once it is closed.
+	ErrClosed = errors.New("report: reporter is closed")
+)
+
+// Async passes Reports to a Reporter from a background
+// goroutine through a bounded queue, so that a slow sink
+// doesn't hold up the requests that failed. When the queue
+// is full the Report is dropped. Errors returned by the
+// wrapped Reporter can't reach the caller and are dropped.
+type Async struct {
+	r       Reporter
+	queue   chan Report
+	done    chan struct{}
+	dropped uint64
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAsync returns an Async passing Reports to r
+// with room for size of them in its queue. Wrap
+// each sink on its own, e.g. Trusted{NewAsync(r, n)},
+// so that Send still tells Trusted sinks apart.
+func NewAsync(r Reporter, size int) *Async {
+	a := &Async{
+		r:     r,
+		queue: make(chan Report, size),
+		done:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *Async) run() {
+	defer close(a.done)
+	for rep := range a.queue {
+		// the request that failed may be over by
+		// now, so its context isn't passed on.
+		_ = a.r.Report(context.Background(), rep)
+	}
+}
+
+// Report queues rep, it doesn't wait for it to be sent.
+func (a *Async) Report(_ context.Context, rep Report) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return ErrClosed
+	}
+	select {
+	case a.queue <- rep:
+		return nil
+	default:
+		atomic.AddUint64(&a.dropped, 1)
+		return ErrQueueFull
+	}
+}
+
+// Dropped returns the number of Reports dropped
+// because the queue was full.
+func (a *Async) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Close stops accepting Reports and waits
+// for the queued ones to be sent.
+func (a *Async) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return nil
+}
@@ -0,0 +1,37 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileReporter appends each Report to
+// a file as a single line of JSON.
+type FileReporter struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+// NewFileReporter opens path for appending,
+// creating it if it doesn't exist.
+func NewFileReporter(path string) (*FileReporter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileReporter{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (r *FileReporter) Report(_ context.Context, rep Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.enc.Encode(rep)
+}
+
+// Close closes the underlying file.
+func (r *FileReporter) Close() error {
+	return r.f.Close()
+}
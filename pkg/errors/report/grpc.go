@@ -0,0 +1,51 @@
+package report
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// MetadataKeys lists the incoming metadata keys that
+// are attached to Reports. Every other key is left out
+// since keys such as authorization or cookie carry
+// credentials. Like errors.KindCodes, it may only
+// be changed during initialization.
+var MetadataKeys = map[string]bool{
+	"user-agent":      true,
+	"accept-language": true,
+	"x-request-id":    true,
+}
+
+// UnaryServerInterceptor returns a unary server interceptor that
+// sends a Report to r for every error returned by a handler. The
+// gRPC method, peer address and the incoming metadata listed in
+// MetadataKeys are attached. Failing to report never changes the
+// handler's result. Wrap slow sinks with NewAsync.
+func UnaryServerInterceptor(r Reporter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			_ = Send(ctx, r, err, grpcMetadata(ctx, info.FullMethod))
+		}
+		return resp, err
+	}
+}
+
+func grpcMetadata(ctx context.Context, method string) map[string]string {
+	md := map[string]string{"grpc.method": method}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		md["peer.address"] = p.Addr.String()
+	}
+	if in, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, v := range in {
+			if MetadataKeys[k] {
+				md["md."+k] = strings.Join(v, ",")
+			}
+		}
+	}
+	return md
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Timeout bounds how long NewHTTPReporter's
+// client waits for the sink to accept a Report.
+var Timeout = 5 * time.Second
+
+// HTTPReporter POSTs each Report as JSON to a URL,
+// such as a local server running NewHandler.
+type HTTPReporter struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPReporter returns an HTTPReporter whose
+// client gives up on a request after Timeout.
+func NewHTTPReporter(url string) *HTTPReporter {
+	return &HTTPReporter{URL: url, Client: &http.Client{Timeout: Timeout}}
+}
+
+func (r *HTTPReporter) Report(ctx context.Context, rep Report) error {
+	body, err := json.Marshal(rep)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, r.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.Client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("report: %s returned %s", r.URL, resp.Status)
+	}
+	return nil
+}
+
+// NewHandler returns an http.Handler that accepts the
+// Reports sent by an HTTPReporter and passes them to next.
+func NewHandler(next Reporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var rep Report
+		if err := json.NewDecoder(req.Body).Decode(&rep); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := next.Report(req.Context(), rep); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
@@ -0,0 +1,40 @@
+package report
+
+// QueueSize is the number of Reports each sink
+// returned by Open can fall behind by before
+// they are dropped.
+var QueueSize = 256
+
+// Open returns a Multi of the sinks a service is
+// configured with: a FileReporter appending to file
+// and an HTTPReporter posting to url, each left out
+// when empty. Every sink is wrapped in its own Async
+// so that reporting never holds up a request. The
+// returned func drains the queues and closes the
+// file. If the file can't be opened the other sinks
+// are still returned along with the error.
+func Open(file, url string) (Multi, func(), error) {
+	var (
+		r       Multi
+		closers []func() error
+		err     error
+	)
+	if file != "" {
+		var f *FileReporter
+		if f, err = NewFileReporter(file); err == nil {
+			q := NewAsync(f, QueueSize)
+			r = append(r, q)
+			closers = append(closers, q.Close, f.Close)
+		}
+	}
+	if url != "" {
+		q := NewAsync(NewHTTPReporter(url), QueueSize)
+		r = append(r, q)
+		closers = append(closers, q.Close)
+	}
+	return r, func() {
+		for _, c := range closers {
+			c()
+		}
+	}, err
+}
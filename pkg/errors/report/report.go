@@ -0,0 +1,123 @@
+// Package report ships server side athens errors to
+// external sinks so that every failure leaves a full
+// record: its stack, Op chain, Kind, severity and the
+// metadata of the request that caused it.
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+// Reporter receives a Report for each server side error.
+type Reporter interface {
+	Report(ctx context.Context, r Report) error
+}
+
+// Report is the record of a single error.
+type Report struct {
+	Time        time.Time              `json:"time"`
+	Message     string                 `json:"message"`
+	Kind        int                    `json:"kind"`
+	KindText    string                 `json:"kindText"`
+	Code        string                 `json:"code,omitempty"`
+	Severity    string                 `json:"severity"`
+	Retryable   bool                   `json:"retryable"`
+	Ops         []string               `json:"ops"`
+	Fingerprint string                 `json:"fingerprint"`
+	Fields      map[string]interface{} `json:"fields,omitempty"`
+	Stack       []string               `json:"stack,omitempty"`
+	// Metadata describes the request that failed,
+	// such as the gRPC method and peer address.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// New builds the Report for err, md holds
+// the metadata of the request that failed.
+// Fields and the message are redacted with
+// the errors package's redaction policy.
+func New(err error, md map[string]string) Report {
+	return newReport(err, md, true)
+}
+
+// NewUnredacted is like New but keeps field
+// values and the message as they are. Its
+// Reports must only be sent to Trusted sinks.
+func NewUnredacted(err error, md map[string]string) Report {
+	return newReport(err, md, false)
+}
+
+func newReport(err error, md map[string]string, redact bool) Report {
+	fields, msg := errors.RedactedFields(err), errors.RedactText(err, err.Error())
+	if !redact {
+		fields, msg = errors.Fields(err), err.Error()
+	}
+
+	r := Report{
+		Time:        time.Now().UTC(),
+		Message:     msg,
+		Kind:        errors.Kind(err),
+		KindText:    errors.KindText(err),
+		Code:        errors.CodeOf(err).String(),
+		Severity:    errors.Severity(err).String(),
+		Retryable:   errors.Retryable(err),
+		Fingerprint: errors.Fingerprint(err),
+		Metadata:    md,
+	}
+	for _, op := range errors.Ops(err) {
+		r.Ops = append(r.Ops, string(op))
+	}
+	for _, kv := range fields {
+		if r.Fields == nil {
+			r.Fields = map[string]interface{}{}
+		}
+		r.Fields[kv.Field.String()] = kv.Value
+	}
+	for _, f := range errors.Stack(err) {
+		r.Stack = append(r.Stack, fmt.Sprintf("%+v", f))
+	}
+	return r
+}
+
+// Trusted marks a Reporter as a trusted sink
+// that Send passes unredacted Reports to.
+type Trusted struct {
+	Reporter
+}
+
+// Send builds the Report for err and sends it to r.
+// Trusted Reporters get an unredacted Report, every
+// other Reporter gets a redacted one. The Reporters
+// in a Multi are each treated on their own.
+func Send(ctx context.Context, r Reporter, err error, md map[string]string) error {
+	switch r := r.(type) {
+	case Multi:
+		var first error
+		for _, reporter := range r {
+			if serr := Send(ctx, reporter, err, md); serr != nil && first == nil {
+				first = serr
+			}
+		}
+		return first
+	case Trusted:
+		return r.Reporter.Report(ctx, NewUnredacted(err, md))
+	}
+	return r.Report(ctx, New(err, md))
+}
+
+// Multi fans each Report out to all of its
+// Reporters, returning the first error seen.
+type Multi []Reporter
+
+func (m Multi) Report(ctx context.Context, r Report) error {
+	var first error
+	for _, reporter := range m {
+		if err := reporter.Report(ctx, r); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
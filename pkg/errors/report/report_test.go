@@ -0,0 +1,123 @@
+package report
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// recorder keeps every Report it gets, blocking
+// until unblock is closed if it is set.
+type recorder struct {
+	mu      sync.Mutex
+	reports []Report
+	unblock chan struct{}
+}
+
+func (r *recorder) Report(_ context.Context, rep Report) error {
+	if r.unblock != nil {
+		<-r.unblock
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports = append(r.reports, rep)
+	return nil
+}
+
+func TestGRPCMetadataAllowList(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer secret",
+		"cookie", "session=secret",
+		"x-request-id", "req-1",
+	))
+	md := grpcMetadata(ctx, "/pb.Orders/NewOrder")
+	if _, ok := md["md.authorization"]; ok {
+		t.Error("authorization was attached")
+	}
+	if _, ok := md["md.cookie"]; ok {
+		t.Error("cookie was attached")
+	}
+	if got := md["md.x-request-id"]; got != "req-1" {
+		t.Errorf("md.x-request-id = %q, want req-1", got)
+	}
+}
+
+func TestAsyncDropsWhenFull(t *testing.T) {
+	rec := &recorder{unblock: make(chan struct{})}
+	a := NewAsync(rec, 1)
+
+	ctx := context.Background()
+	rep := New(errors.E("op", "boom"), nil)
+	// the first Report is taken by the blocked worker,
+	// the second fills the queue.
+	var full bool
+	for i := 0; i < 3; i++ {
+		if err := a.Report(ctx, rep); err == ErrQueueFull {
+			full = true
+		}
+	}
+	if !full || a.Dropped() == 0 {
+		t.Fatalf("no Report was dropped, Dropped = %d", a.Dropped())
+	}
+
+	close(rec.unblock)
+	a.Close()
+	if got, want := len(rec.reports), 3-int(a.Dropped()); got != want {
+		t.Errorf("sent %d Reports, want %d", got, want)
+	}
+	if err := a.Report(ctx, rep); err != ErrClosed {
+		t.Errorf("Report after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestNewRedactsFields(t *testing.T) {
+	r := New(errors.E("op", "order for customer 12345 failed", errors.C("12345")), nil)
+	if v := r.Fields[errors.CustomerIDField.String()]; v == "12345" {
+		t.Error("customer ID field was not redacted")
+	}
+	if r.Message == "order for customer 12345 failed" {
+		t.Error("customer ID was not redacted from the message")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "errors.jsonl")
+	r, closeReporters, err := Open(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Report(context.Background(), New(errors.E("op", "boom"), nil)); err != nil {
+		t.Fatal(err)
+	}
+	closeReporters()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(b), "\n"); n != 1 {
+		t.Errorf("%d Reports written, want 1", n)
+	}
+
+	r, closeReporters, err = Open(filepath.Join(dir, "missing", "errors.jsonl"), "http://localhost/reports")
+	if err == nil {
+		t.Error("Open didn't fail for a file in a missing directory")
+	}
+	if len(r) != 1 {
+		t.Errorf("%d sinks, want the HTTP one", len(r))
+	}
+	closeReporters()
+}
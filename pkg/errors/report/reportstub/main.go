@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/jwenz723/errhandling/pkg/errors/report"
+)
+
+// A local stand-in for an error reporting service. It accepts the
+// Reports sent by report.HTTPReporter and appends them to a JSONL file.
+func main() {
+	fs := flag.NewFlagSet("reportstub", flag.ExitOnError)
+	addr := fs.String("addr", ":8090", "HTTP listen address")
+	out := fs.String("out", "reports.jsonl", "file the received reports are appended to")
+	fs.Parse(os.Args[1:])
+
+	r, err := report.NewFileReporter(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer r.Close()
+
+	log.Printf("accepting reports on %s, writing to %s", *addr, *out)
+	log.Fatal(http.ListenAndServe(*addr, report.NewHandler(r)))
+}
@@ -0,0 +1,92 @@
+package errors
+
+import "strconv"
+
+// Retry overrides whether an Error is safe to
+// retry, e.g. E(op, err, Retry(false)) for a
+// failure that retrying would make worse.
+type Retry bool
+
+// KindRetries holds the default retryability of
+// each Kind. Kinds that are missing are not safe
+// to retry. KindDeadlineExceeded is left out since
+// the timed out call may still have taken effect,
+// so retrying it could e.g. place an order twice.
+// Entries are changed the same way as KindCodes.
+var KindRetries = map[int]bool{
+	KindRateLimit:   true,
+	KindUnavailable: true,
+}
+
+// Retryable reports whether the operation that
+// failed with err is safe to retry. The outermost
+// Retry set in err's chain wins. Otherwise, when the
+// chain has an Error with a Kind, an Error with a
+// RetryAfter is retryable and the default for the
+// Kind is used. Only without a Kind is an error that
+// implements Temporary() bool, such as net.Error,
+// consulted, so that e.g. context.DeadlineExceeded
+// wrapped with KindDeadlineExceeded is not retried.
+func Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if src, ok := Find(err, func(err error) bool {
+		e, ok := asError(err)
+		return ok && e.Retry != nil
+	}); ok {
+		e, _ := asError(src)
+		return bool(*e.Retry)
+	}
+	if !hasKind(err) {
+		if src, ok := Find(err, func(err error) bool {
+			if _, ok := asError(err); ok {
+				return false
+			}
+			_, ok := err.(interface{ Temporary() bool })
+			return ok
+		}); ok {
+			return src.(interface{ Temporary() bool }).Temporary()
+		}
+	}
+	if RetryAfter(err) > 0 {
+		return true
+	}
+	return KindRetries[Kind(err)]
+}
+
+// hasKind reports whether err's chain has an
+// Error with a Kind or a MultiError.
+func hasKind(err error) bool {
+	_, ok := Find(err, func(err error) bool {
+		if e, ok := asError(err); ok {
+			return e.Kind != 0
+		}
+		_, ok := asMulti(err)
+		return ok
+	})
+	return ok
+}
+
+// Temporary is an alias of Retryable
+// named after the net.Error method.
+func Temporary(err error) bool {
+	return Retryable(err)
+}
+
+// Temporary implements the interface checked
+// by net/http and friends for transient errors.
+func (e Error) Temporary() bool {
+	return Retryable(e)
+}
+
+// parseRetry reads the retryable
+// metadata of a remote ErrorInfo.
+func parseRetry(v string) *Retry {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil
+	}
+	r := Retry(b)
+	return &r
+}
@@ -0,0 +1,39 @@
+package errors_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+// urlTimeout is what net/http returns for a
+// request that timed out.
+var urlTimeout = &url.Error{Op: "Post", URL: "http://orders", Err: context.DeadlineExceeded}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limit", errors.E("op", "slow down", errors.KindRateLimit), true},
+		{"unavailable", errors.E("op", "down", errors.KindUnavailable), true},
+		{"deadline exceeded", errors.E("op", "timed out", errors.KindDeadlineExceeded), false},
+		{"bad request", errors.E("op", "bad", errors.KindBadRequest), false},
+		{"retry after", errors.E("op", "busy", errors.KindUnexpected, time.Second), true},
+		{"override", errors.E("op", errors.E("inner", "down", errors.KindUnavailable), errors.Retry(false)), false},
+		{"inner override", errors.E("op", errors.E("inner", "down", errors.Retry(true)), errors.KindBadRequest), true},
+		{"temporary with kind", errors.E("op", context.DeadlineExceeded, errors.KindDeadlineExceeded), false},
+		{"url timeout with kind", errors.E("op", urlTimeout, errors.KindDeadlineExceeded), false},
+		{"temporary without kind", errors.E("op", urlTimeout), true},
+	}
+	for _, tt := range tests {
+		if got := errors.Retryable(tt.err); got != tt.want {
+			t.Errorf("%s: Retryable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
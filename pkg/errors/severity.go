@@ -0,0 +1,87 @@
+package errors
+
+import "github.com/go-kit/kit/log/level"
+
+// KindSeverities holds the default log level
+// for each Kind. It is used by Severity when no
+// severity was set in an error's chain. Kinds
+// that are missing default to Error.
+var KindSeverities = map[int]level.Value{
+	KindNotFound:         level.InfoValue(),
+	KindBadRequest:       level.InfoValue(),
+	KindAlreadyExists:    level.InfoValue(),
+	KindRedirect:         level.InfoValue(),
+	KindRateLimit:        level.WarnValue(),
+	KindUnavailable:      level.WarnValue(),
+	KindDeadlineExceeded: level.WarnValue(),
+}
+
+// KindSeverity returns the default
+// log level for kind.
+func KindSeverity(kind int) level.Value {
+	if v, ok := KindSeverities[kind]; ok {
+		return v
+	}
+	return level.ErrorValue()
+}
+
+// MaxSeverity returns the most severe log level
+// set anywhere in err's chain, including the
+// children of a MultiError. If none was set
+// then the default for its Kind is used.
+func MaxSeverity(err error) level.Value {
+	var max level.Value
+	Walk(err, func(err error) bool {
+		if e, ok := asError(err); ok && CompareSeverity(e.Severity, max) > 0 {
+			max = e.Severity
+		}
+		if m, ok := asMulti(err); ok {
+			for _, child := range m.Errors {
+				if v := MaxSeverity(child); CompareSeverity(v, max) > 0 {
+					max = v
+				}
+			}
+		}
+		return true
+	})
+	if max == nil {
+		return KindSeverity(Kind(err))
+	}
+	return max
+}
+
+// CompareSeverity orders go-kit levels from
+// debug to error. It returns a negative number
+// when a is less severe than b, zero when they
+// are equal and a positive number otherwise.
+// A nil level is less severe than any other.
+func CompareSeverity(a, b level.Value) int {
+	return rank(a) - rank(b)
+}
+
+func rank(v level.Value) int {
+	if v == nil {
+		return 0
+	}
+	switch v.String() {
+	case level.DebugValue().String():
+		return 1
+	case level.InfoValue().String():
+		return 2
+	case level.WarnValue().String():
+		return 3
+	default:
+		return 4
+	}
+}
+
+// ParseSeverity returns the go-kit level named
+// s, such as "warn", or nil if there is none.
+func ParseSeverity(s string) level.Value {
+	for _, v := range []level.Value{level.DebugValue(), level.InfoValue(), level.WarnValue(), level.ErrorValue()} {
+		if v.String() == s {
+			return v
+		}
+	}
+	return nil
+}
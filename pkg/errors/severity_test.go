@@ -0,0 +1,70 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/go-kit/kit/log/level"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want level.Value
+	}{
+		{"kind default", errors.E("op", "missing", errors.KindNotFound), level.InfoValue()},
+		{"unexpected default", errors.E("op", "boom"), level.ErrorValue()},
+		{"outermost wins", errors.E("outer", errors.E("inner", "boom", level.ErrorValue()), level.InfoValue()), level.InfoValue()},
+		{"nearest child", errors.E("outer", errors.E("inner", "missing", errors.KindNotFound, level.WarnValue())), level.WarnValue()},
+		{"multi max", errors.E("outer", errors.Multi("batch",
+			errors.E("line1", "bad", errors.KindBadRequest),
+			errors.E("line2", "boom", level.ErrorValue()),
+		)), level.ErrorValue()},
+		{"outermost over multi", errors.E("outer", errors.Multi("batch",
+			errors.E("line1", "boom", level.ErrorValue()),
+		), level.InfoValue()), level.InfoValue()},
+	}
+	for _, tt := range tests {
+		if got := errors.Severity(tt.err); got.String() != tt.want.String() {
+			t.Errorf("%s: Severity = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMaxSeverity(t *testing.T) {
+	err := errors.E("outer", errors.E("inner", "boom", level.ErrorValue()), level.InfoValue())
+	if got := errors.MaxSeverity(err); got.String() != level.ErrorValue().String() {
+		t.Errorf("MaxSeverity = %v, want error", got)
+	}
+	if got := errors.MaxSeverity(errors.E("op", "down", errors.KindUnavailable)); got.String() != level.WarnValue().String() {
+		t.Errorf("MaxSeverity without a severity = %v, want the Kind default warn", got)
+	}
+}
+
+func TestExpect(t *testing.T) {
+	missing := errors.E("op", "missing", errors.KindNotFound, level.ErrorValue())
+	if got := errors.Expect(missing, errors.KindBadRequest, errors.KindNotFound); got.String() != level.InfoValue().String() {
+		t.Errorf("Expect of an expected Kind = %v, want info", got)
+	}
+	down := errors.E("op", "down", errors.KindUnavailable)
+	if got := errors.Expect(down, errors.KindNotFound); got.String() != errors.Severity(down).String() {
+		t.Errorf("Expect of another Kind = %v, want its Severity %v", got, errors.Severity(down))
+	}
+}
+
+func TestCompareSeverity(t *testing.T) {
+	order := []level.Value{nil, level.DebugValue(), level.InfoValue(), level.WarnValue(), level.ErrorValue()}
+	for i := 1; i < len(order); i++ {
+		if errors.CompareSeverity(order[i-1], order[i]) >= 0 {
+			t.Errorf("%v isn't less severe than %v", order[i-1], order[i])
+		}
+	}
+	if got := errors.ParseSeverity("warn"); got == nil || got.String() != "warn" {
+		t.Errorf("ParseSeverity(warn) = %v", got)
+	}
+	if got := errors.ParseSeverity("loud"); got != nil {
+		t.Errorf("ParseSeverity(loud) = %v, want nil", got)
+	}
+}
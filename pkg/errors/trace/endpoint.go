@@ -0,0 +1,63 @@
+package trace
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+)
+
+// TraceServer returns an endpoint.Middleware that traces each
+// call in a span named operationName. The span a transport put
+// in the context, e.g. with go-kit's GRPCToContext, is reused,
+// otherwise one is started. A transport error, or the error of
+// an endpoint.Failer response, is recorded with RecordError.
+func TraceServer(tracer opentracing.Tracer, operationName string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			span := opentracing.SpanFromContext(ctx)
+			if span == nil {
+				span = tracer.StartSpan(operationName, ext.SpanKindRPCServer)
+				ctx = opentracing.ContextWithSpan(ctx, span)
+			} else {
+				span.SetOperationName(operationName)
+			}
+			defer span.Finish()
+
+			response, err := next(ctx, request)
+			RecordError(span, failed(response, err))
+			return response, err
+		}
+	}
+}
+
+// TraceClient returns an endpoint.Middleware that traces each
+// call in a span named operationName, a child of the span in
+// the context if any. Transports send it to the server with
+// e.g. go-kit's ContextToGRPC. Errors are recorded as in
+// TraceServer.
+func TraceClient(tracer opentracing.Tracer, operationName string) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			span, ctx := opentracing.StartSpanFromContextWithTracer(ctx, tracer, operationName, ext.SpanKindRPCClient)
+			defer span.Finish()
+
+			response, err := next(ctx, request)
+			RecordError(span, failed(response, err))
+			return response, err
+		}
+	}
+}
+
+// failed returns err, or the error of
+// response if it is an endpoint.Failer.
+func failed(response interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+	if f, ok := response.(endpoint.Failer); ok {
+		return f.Failed()
+	}
+	return nil
+}
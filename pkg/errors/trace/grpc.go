@@ -0,0 +1,80 @@
+package trace
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerInterceptor returns a unary server interceptor that
+// traces each call in a span named after the method, joining the
+// trace of the client if the metadata carries one. The error of
+// the handler is recorded with RecordError, so the interceptor
+// must run inside LocaleUnaryServerInterceptor to still get the
+// athens errors.
+func UnaryServerInterceptor(tracer opentracing.Tracer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		parent, _ := tracer.Extract(opentracing.HTTPHeaders, metadataCarrier(md))
+		span := tracer.StartSpan(info.FullMethod, ext.RPCServerOption(parent))
+		defer span.Finish()
+
+		resp, err := handler(opentracing.ContextWithSpan(ctx, span), req)
+		RecordError(span, err)
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor returns a unary client interceptor that
+// traces each call in a span named after the method, a child of
+// the span in the context if any, and sends it to the server in
+// the metadata. A status error is recorded as the athens error
+// it carries, so the span gets the Op chain of the server.
+func UnaryClientInterceptor(tracer opentracing.Tracer) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		span, ctx := opentracing.StartSpanFromContextWithTracer(ctx, tracer, method, ext.SpanKindRPCClient)
+		defer span.Finish()
+
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if ok {
+			md = md.Copy()
+		} else {
+			md = metadata.MD{}
+		}
+		_ = tracer.Inject(span.Context(), opentracing.HTTPHeaders, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if remote, ok := errors.FromError(err); ok {
+			RecordError(span, remote)
+		} else {
+			RecordError(span, err)
+		}
+		return err
+	}
+}
+
+// metadataCarrier lets a Tracer read and
+// write span contexts in gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Set(key, val string) {
+	key = strings.ToLower(key)
+	c[key] = append(c[key], val)
+}
+
+func (c metadataCarrier) ForeachKey(handler func(key, val string) error) error {
+	for k, vs := range c {
+		for _, v := range vs {
+			if err := handler(k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
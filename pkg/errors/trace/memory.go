@@ -0,0 +1,83 @@
+package trace
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+// InMemory is a Tracer that keeps finished spans in memory
+// instead of exporting them to a collector, so that tests
+// and examples can assert on what RecordError recorded.
+type InMemory struct {
+	*mocktracer.MockTracer
+}
+
+// NewInMemory returns an empty InMemory Tracer.
+func NewInMemory() InMemory {
+	return InMemory{mocktracer.New()}
+}
+
+// Failed returns the finished spans
+// that are tagged with the error tag.
+func (m InMemory) Failed() []*mocktracer.MockSpan {
+	var spans []*mocktracer.MockSpan
+	for _, span := range m.FinishedSpans() {
+		if failed, _ := span.Tag("error").(bool); failed {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// Events returns the value of key in each event of span
+// named event, in the order they were logged. For example
+// Events(span, OpEvent, "op") returns the Op chain.
+func Events(span *mocktracer.MockSpan, event, key string) []string {
+	var vals []string
+	for _, rec := range span.Logs() {
+		var name, val string
+		for _, kv := range rec.Fields {
+			switch kv.Key {
+			case "event":
+				name = kv.ValueString
+			case key:
+				val = kv.ValueString
+			}
+		}
+		if name == event {
+			vals = append(vals, val)
+		}
+	}
+	return vals
+}
+
+// Dump writes each finished span to w with its tags and
+// the Op chain of its error, one line per span. The stack
+// is left out, use Events(span, StackEvent, "stack").
+func (m InMemory) Dump(w io.Writer) error {
+	for _, span := range m.FinishedSpans() {
+		tags := span.Tags()
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "trace=%d span=%d parent=%d name=%s", span.SpanContext.TraceID, span.SpanContext.SpanID, span.ParentID, span.OperationName)
+		for _, k := range keys {
+			fmt.Fprintf(&b, " %s=%v", k, tags[k])
+		}
+		if ops := Events(span, OpEvent, "op"); len(ops) > 0 {
+			fmt.Fprintf(&b, " ops=%q", strings.Join(ops, ": "))
+		}
+		if _, err := fmt.Fprintln(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Package trace records athens errors on OpenTracing spans,
+// so that a trace shows not only that a call failed but its
+// Kind, which Op in the chain it failed in and the stack of
+// where it was built.
+package trace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/opentracing/opentracing-go/log"
+)
+
+// Tags RecordError sets on the span of a
+// failed call, next to the standard error tag.
+const (
+	KindTag        = "error.kind"
+	KindTextTag    = "error.kind_text"
+	CodeTag        = "error.code"
+	FingerprintTag = "error.fingerprint"
+	RetryableTag   = "error.retryable"
+)
+
+// Events RecordError logs on the span of a failed
+// call, next to the standard error event. OpEvent
+// is logged once for each Op in the chain.
+const (
+	OpEvent    = "error.op"
+	StackEvent = "error.stack"
+)
+
+// RecordError marks span as failed with the Kind of err. It
+// logs an error event with the redacted message and fields,
+// an OpEvent for each Op in the chain, outermost first, and
+// a StackEvent with the innermost stack. The last OpEvent is
+// the layer the error was built in. It does nothing if err
+// is nil.
+func RecordError(span opentracing.Span, err error) {
+	if span == nil || err == nil {
+		return
+	}
+	ext.Error.Set(span, true)
+	span.SetTag(KindTag, errors.Kind(err))
+	span.SetTag(KindTextTag, errors.KindText(err))
+	if code := errors.CodeOf(err); code != "" {
+		span.SetTag(CodeTag, code.String())
+	}
+	span.SetTag(FingerprintTag, errors.Fingerprint(err))
+	span.SetTag(RetryableTag, errors.Retryable(err))
+
+	fields := []log.Field{
+		log.Event("error"),
+		log.Message(errors.RedactText(err, err.Error())),
+		log.String("severity", errors.Severity(err).String()),
+	}
+	for _, kv := range errors.RedactedFields(err) {
+		fields = append(fields, log.String(kv.Field.String(), fmt.Sprint(kv.Value)))
+	}
+	span.LogFields(fields...)
+
+	depth := 0
+	for _, op := range errors.Ops(err) {
+		if op == "" {
+			continue
+		}
+		span.LogFields(log.Event(OpEvent), log.String("op", string(op)), log.Int("depth", depth))
+		depth++
+	}
+
+	if st := errors.Stack(err); len(st) > 0 {
+		frames := make([]string, len(st))
+		for i, f := range st {
+			frames[i] = fmt.Sprintf("%+v", f)
+		}
+		span.LogFields(log.Event(StackEvent), log.String("stack", strings.Join(frames, "\n")))
+	}
+}
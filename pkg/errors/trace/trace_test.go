@@ -0,0 +1,80 @@
+package trace_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"github.com/jwenz723/errhandling/pkg/errors/trace"
+)
+
+func TestRecordError(t *testing.T) {
+	tracer := trace.NewInMemory()
+	span := tracer.StartSpan("NewOrder")
+	trace.RecordError(span, errors.E("levelOne",
+		errors.E("levelTwo",
+			errors.E("levelThree",
+				errors.E("levelFour", "quantity must be positive", errors.KindBadRequest)))))
+	span.Finish()
+
+	failed := tracer.Failed()
+	if len(failed) != 1 {
+		t.Fatalf("%d failed spans, want 1", len(failed))
+	}
+	s := failed[0]
+	if got, want := s.Tag(trace.KindTag), errors.KindBadRequest; got != want {
+		t.Errorf("%s = %v, want %v", trace.KindTag, got, want)
+	}
+	if got, want := s.Tag(trace.RetryableTag), false; got != want {
+		t.Errorf("%s = %v, want %v", trace.RetryableTag, got, want)
+	}
+	wantOps := []string{"levelOne", "levelTwo", "levelThree", "levelFour"}
+	if got := trace.Events(s, trace.OpEvent, "op"); !reflect.DeepEqual(got, wantOps) {
+		t.Errorf("ops = %v, want %v", got, wantOps)
+	}
+	if st := trace.Events(s, trace.StackEvent, "stack"); len(st) != 1 || st[0] == "" {
+		t.Errorf("stack events = %q, want one stack", st)
+	}
+}
+
+func TestRecordErrorNil(t *testing.T) {
+	tracer := trace.NewInMemory()
+	span := tracer.StartSpan("NewOrder")
+	trace.RecordError(span, nil)
+	span.Finish()
+
+	if failed := tracer.Failed(); len(failed) != 0 {
+		t.Errorf("%d failed spans, want 0", len(failed))
+	}
+	if logs := tracer.FinishedSpans()[0].Logs(); len(logs) != 0 {
+		t.Errorf("%d events logged, want 0", len(logs))
+	}
+}
+
+// failer is a response that carries its error,
+// as the orders service responses do.
+type failer struct{ err error }
+
+func (f failer) Failed() error { return f.err }
+
+func TestTraceServerFailer(t *testing.T) {
+	tracer := trace.NewInMemory()
+	ep := trace.TraceServer(tracer, "NewOrder")(func(context.Context, interface{}) (interface{}, error) {
+		return failer{errors.E("service.NewOrder", "order not found", errors.KindNotFound)}, nil
+	})
+	if _, err := ep(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := tracer.Failed()
+	if len(failed) != 1 {
+		t.Fatalf("%d failed spans, want 1", len(failed))
+	}
+	if got, want := failed[0].OperationName, "NewOrder"; got != want {
+		t.Errorf("span name = %q, want %q", got, want)
+	}
+	if got, want := failed[0].Tag(trace.KindTag), errors.KindNotFound; got != want {
+		t.Errorf("%s = %v, want %v", trace.KindTag, got, want)
+	}
+}
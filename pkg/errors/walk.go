@@ -0,0 +1,106 @@
+package errors
+
+import "reflect"
+
+// MaxDepth is the maximum number of errors
+// Walk will visit in a single chain. It guards
+// against chains that are pathologically deep.
+var MaxDepth = 64
+
+// Visitor is called by Walk for every error in
+// a chain, outermost first. Returning false stops
+// the walk.
+type Visitor func(err error) bool
+
+// Walk calls visit for err and for every error
+// it wraps. Any error implementing Unwrap() error
+// or pkg/errors' Cause() error is followed, so
+// foreign wrappers such as fmt.Errorf("%w") do not
+// end the walk. Walk stops after MaxDepth errors
+// or when an error it already visited is seen again.
+func Walk(err error, visit Visitor) {
+	var seen map[uintptr]bool
+	for depth := 0; err != nil && depth < MaxDepth; depth++ {
+		// a chain can only loop back on itself through
+		// a pointer, so only pointers need to be tracked.
+		if v := reflect.ValueOf(err); v.Kind() == reflect.Ptr {
+			if seen == nil {
+				seen = map[uintptr]bool{}
+			}
+			if seen[v.Pointer()] {
+				return
+			}
+			seen[v.Pointer()] = true
+		}
+
+		if !visit(err) {
+			return
+		}
+		err = next(err)
+	}
+}
+
+// Find returns the first error in err's chain
+// for which match returns true.
+func Find(err error, match Visitor) (error, bool) {
+	var found error
+	Walk(err, func(err error) bool {
+		if match(err) {
+			found = err
+			return false
+		}
+		return true
+	})
+	return found, found != nil
+}
+
+// findError returns the first Error in err's
+// chain for which match returns true.
+func findError(err error, match func(e Error) bool) (Error, bool) {
+	var found Error
+	_, ok := Find(err, func(err error) bool {
+		e, ok := asError(err)
+		if ok && match(e) {
+			found = e
+			return true
+		}
+		return false
+	})
+	return found, ok
+}
+
+// errorsOf returns every Error in err's chain,
+// outermost first.
+func errorsOf(err error) []Error {
+	var errs []Error
+	Walk(err, func(err error) bool {
+		if e, ok := asError(err); ok {
+			errs = append(errs, e)
+		}
+		return true
+	})
+	return errs
+}
+
+func asError(err error) (Error, bool) {
+	switch e := err.(type) {
+	case Error:
+		return e, true
+	case *Error:
+		if e != nil {
+			return *e, true
+		}
+	}
+	return Error{}, false
+}
+
+// next returns the error wrapped by err, or nil.
+func next(err error) error {
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	case interface{ Cause() error }:
+		return e.Cause()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	pkgerrors "github.com/pkg/errors"
+)
+
+// loopError wraps itself through a pointer.
+type loopError struct {
+	next error
+}
+
+func (e *loopError) Error() string { return "loop" }
+func (e *loopError) Unwrap() error { return e.next }
+
+func TestWalkStopsOnCycle(t *testing.T) {
+	a := &loopError{}
+	b := &loopError{next: a}
+	a.next = b
+
+	var n int
+	errors.Walk(a, func(error) bool {
+		n++
+		return true
+	})
+	if n != 2 {
+		t.Fatalf("visited %d errors, want 2", n)
+	}
+	if kind := errors.Kind(a); kind != errors.KindUnexpected {
+		t.Fatalf("Kind = %d, want %d", kind, errors.KindUnexpected)
+	}
+}
+
+func TestWalkStopsAtMaxDepth(t *testing.T) {
+	var err error = errors.E("inner", "base", errors.KindNotFound)
+	for i := 0; i < errors.MaxDepth*2; i++ {
+		err = fmt.Errorf("layer %d: %w", i, err)
+	}
+
+	var n int
+	errors.Walk(err, func(error) bool {
+		n++
+		return true
+	})
+	if n != errors.MaxDepth {
+		t.Fatalf("visited %d errors, want MaxDepth %d", n, errors.MaxDepth)
+	}
+	// the Error is beyond MaxDepth so it is never seen.
+	if kind := errors.Kind(err); kind != errors.KindUnexpected {
+		t.Fatalf("Kind = %d, want %d", kind, errors.KindUnexpected)
+	}
+}
+
+func TestWalkFollowsForeignWrappers(t *testing.T) {
+	err := pkgerrors.Wrap(fmt.Errorf("wrapped: %w", errors.E("inner", "base", errors.KindNotFound)), "cause")
+	if kind := errors.Kind(err); kind != errors.KindNotFound {
+		t.Fatalf("Kind = %d, want %d", kind, errors.KindNotFound)
+	}
+}
+
+func TestWalkStopsWhenVisitReturnsFalse(t *testing.T) {
+	err := errors.E("outer", errors.E("inner", "base"))
+	var n int
+	errors.Walk(err, func(error) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Fatalf("visited %d errors, want 1", n)
+	}
+}
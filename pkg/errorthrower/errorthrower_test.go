@@ -0,0 +1,15 @@
+package errorthrower
+
+import (
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+func TestSomeErrorAs(t *testing.T) {
+	err := errors.E("service.NewOrder", errors.E("db.Insert", SomeError()))
+	var te *testError
+	if !errors.As(err, &te) {
+		t.Fatal("errors.As didn't find the testError returned from LevelThree")
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	_ "github.com/jwenz723/errhandling/pkg/ordercodes"
+)
+
+// Generates the documentation of every error code
+// registered by the Orders API, as Markdown or JSON.
+func main() {
+	fs := flag.NewFlagSet("docgen", flag.ExitOnError)
+	format := fs.String("format", "md", "output format, md or json")
+	out := fs.String("out", "", "file to write to, stdout if empty")
+	fs.Parse(os.Args[1:])
+
+	w := io.Writer(os.Stdout)
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var err error
+	switch *format {
+	case "md":
+		err = writeMarkdown(w, errors.Catalog())
+	case "json":
+		err = writeJSON(w, errors.Catalog())
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// codeDoc is the documented form of an errors.CodeDef.
+type codeDoc struct {
+	Code        string `json:"code"`
+	Kind        int    `json:"kind"`
+	KindText    string `json:"kindText"`
+	GrpcCode    string `json:"grpcCode"`
+	Severity    string `json:"severity"`
+	Retryable   bool   `json:"retryable"`
+	Message     string `json:"message"`
+	Description string `json:"description"`
+}
+
+func docs(defs []errors.CodeDef) []codeDoc {
+	var ds []codeDoc
+	for _, def := range defs {
+		e := errors.E("", def.Code)
+		ds = append(ds, codeDoc{
+			Code:        def.Code.String(),
+			Kind:        def.Kind,
+			KindText:    errors.KindText(e),
+			GrpcCode:    errors.Code(e).String(),
+			Severity:    errors.Severity(e).String(),
+			Retryable:   errors.Retryable(e),
+			Message:     def.Message,
+			Description: def.Description,
+		})
+	}
+	return ds
+}
+
+func writeJSON(w io.Writer, defs []errors.CodeDef) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(docs(defs))
+}
+
+func writeMarkdown(w io.Writer, defs []errors.CodeDef) error {
+	var b strings.Builder
+	b.WriteString("# Orders API error codes\n\n")
+	b.WriteString("| Code | HTTP | gRPC | Severity | Retryable | Message | Description |\n")
+	b.WriteString("|------|------|------|----------|-----------|---------|-------------|\n")
+	for _, d := range docs(defs) {
+		fmt.Fprintf(&b, "| `%s` | %d %s | %s | %s | %t | %s | %s |\n",
+			d.Code, d.Kind, d.KindText, d.GrpcCode, d.Severity, d.Retryable, md(d.Message), md(d.Description))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// md escapes s for use in a Markdown table cell.
+func md(s string) string {
+	return strings.Replace(s, "|", `\|`, -1)
+}
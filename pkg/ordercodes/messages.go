@@ -0,0 +1,24 @@
+package ordercodes
+
+import "github.com/jwenz723/errhandling/pkg/errors"
+
+// Translations of the Orders API messages. The English
+// messages are the ones registered with each CodeDef.
+func init() {
+	errors.RegisterMessages("es", map[string]string{
+		string(CustomerRequired): "se requiere un ID de cliente",
+		string(OrderRejected):    "el pedido del cliente {{.customerID}} fue rechazado",
+		string(Internal):         "no se pudo realizar el pedido",
+		"BAD_REQUEST":            "la solicitud no es válida",
+		"NOT_FOUND":              "no se encontró el recurso solicitado",
+		"INTERNAL_SERVER_ERROR":  "ocurrió un error inesperado",
+	})
+	errors.RegisterMessages("fr", map[string]string{
+		string(CustomerRequired): "un identifiant client est requis",
+		string(OrderRejected):    "la commande du client {{.customerID}} a été refusée",
+		string(Internal):         "la commande n'a pas pu être passée",
+		"BAD_REQUEST":            "la requête est invalide",
+		"NOT_FOUND":              "la ressource demandée est introuvable",
+		"INTERNAL_SERVER_ERROR":  "une erreur inattendue s'est produite",
+	})
+}
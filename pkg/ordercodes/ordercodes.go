@@ -0,0 +1,48 @@
+// Package ordercodes registers the error codes
+// returned by the Orders API with the errors
+// catalog. Importing it is enough to make the
+// codes known to errors.E and errors.FromStatus.
+package ordercodes
+
+import (
+	"github.com/go-kit/kit/log/level"
+	"github.com/jwenz723/errhandling/pkg/errors"
+)
+
+const (
+	// CustomerRequired is returned when a
+	// request doesn't name a customer.
+	CustomerRequired = errors.ErrorCode("ORDERS_CUSTOMER_REQUIRED")
+	// OrderRejected is returned when an
+	// order can't be placed for a customer.
+	OrderRejected = errors.ErrorCode("ORDERS_ORDER_REJECTED")
+	// Internal is returned when an order failed
+	// for a reason the client can't act on.
+	Internal = errors.ErrorCode("ORDERS_INTERNAL")
+)
+
+func init() {
+	errors.Register(
+		errors.CodeDef{
+			Code:        CustomerRequired,
+			Kind:        errors.KindBadRequest,
+			Message:     "a customer ID is required",
+			Severity:    level.InfoValue(),
+			Description: "NewOrder was called with an empty customer ID.",
+		},
+		errors.CodeDef{
+			Code:        OrderRejected,
+			Kind:        errors.KindBadRequest,
+			Message:     "the order for customer {{.customerID}} was rejected",
+			Severity:    level.InfoValue(),
+			Description: "The order was rejected for the customer, retrying won't help.",
+		},
+		errors.CodeDef{
+			Code:        Internal,
+			Kind:        errors.KindUnexpected,
+			Message:     "the order could not be placed",
+			Severity:    level.ErrorValue(),
+			Description: "An unexpected failure in the Orders service.",
+		},
+	)
+}
@@ -0,0 +1,132 @@
+// Package requestid generates request IDs and carries them
+// through clients, servers, logs and athens errors, so that
+// a client error can be linked to the server logs by one ID.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the gRPC metadata key
+// a request ID is sent under.
+const MetadataKey = "x-request-id"
+
+// Header is the HTTP header a
+// request ID is sent under.
+const Header = "X-Request-Id"
+
+type idKey struct{}
+
+// New returns a new random request ID.
+func New() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// NewContext returns a copy of ctx carrying id. The id
+// is also attached to every error built with errors.EC
+// from the returned context.
+func NewContext(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, idKey{}, id)
+	return errors.WithFields(ctx, errors.RequestIDField.V(id))
+}
+
+// FromContext returns the request ID carried by ctx.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(idKey{}).(string)
+	return id, ok
+}
+
+// ensure returns ctx carrying a request ID, the
+// one in ctx or id if set, or else a new one.
+func ensure(ctx context.Context, id string) (context.Context, string) {
+	if cur, ok := FromContext(ctx); ok {
+		return ctx, cur
+	}
+	if id == "" {
+		id = New()
+	}
+	return NewContext(ctx, id), id
+}
+
+// UnaryServerInterceptor returns a unary server interceptor
+// that reads the request ID from the incoming metadata, or
+// generates one, stores it in the context and sends it back
+// in the response header. The ID is attached to errors
+// returned by the handler that don't carry it yet, so
+// that it reaches the client in the status details.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(MetadataKey); len(v) > 0 {
+				id = v[0]
+			}
+		}
+		ctx, id = ensure(ctx, id)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id))
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if _, ok := errors.Lookup(err, errors.RequestIDField); !ok {
+				err = errors.WithField(err, errors.RequestIDField.V(id))
+			}
+		}
+		return resp, err
+	}
+}
+
+// UnaryClientInterceptor returns a unary client interceptor
+// that sends the request ID of the context, generating one
+// if it has none.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, id := ensure(ctx, "")
+		ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// GRPCServerBefore reads the request ID from md, or generates
+// one, and stores it in ctx. It is a go-kit grpc.ServerRequestFunc.
+func GRPCServerBefore(ctx context.Context, md metadata.MD) context.Context {
+	var id string
+	if v := md.Get(MetadataKey); len(v) > 0 {
+		id = v[0]
+	}
+	ctx, _ = ensure(ctx, id)
+	return ctx
+}
+
+// GRPCClientBefore sets the request ID of ctx in md, generating
+// one if it has none. It is a go-kit grpc.ClientRequestFunc.
+func GRPCClientBefore(ctx context.Context, md *metadata.MD) context.Context {
+	ctx, id := ensure(ctx, "")
+	md.Set(MetadataKey, id)
+	return ctx
+}
+
+// HTTPServerBefore reads the request ID from the request header,
+// or generates one, and stores it in ctx. It is a go-kit
+// http.RequestFunc for servers.
+func HTTPServerBefore(ctx context.Context, r *http.Request) context.Context {
+	ctx, _ = ensure(ctx, r.Header.Get(Header))
+	return ctx
+}
+
+// HTTPClientBefore sets the request ID of ctx in the request
+// header, generating one if it has none. It is a go-kit
+// http.RequestFunc for clients.
+func HTTPClientBefore(ctx context.Context, r *http.Request) context.Context {
+	ctx, id := ensure(ctx, "")
+	r.Header.Set(Header, id)
+	return ctx
+}
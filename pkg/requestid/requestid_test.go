@@ -0,0 +1,105 @@
+package requestid
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/jwenz723/errhandling/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestEnsure(t *testing.T) {
+	ctx, id := ensure(context.Background(), "")
+	if len(id) != 16 {
+		t.Errorf("generated ID %q, want 16 hex digits", id)
+	}
+	if got, _ := FromContext(ctx); got != id {
+		t.Errorf("FromContext = %q, want %q", got, id)
+	}
+
+	if _, got := ensure(ctx, "req-2"); got != id {
+		t.Errorf("ensure replaced the ID of the context with %q", got)
+	}
+	if _, got := ensure(context.Background(), "req-2"); got != "req-2" {
+		t.Errorf("ensure = %q, want the given req-2", got)
+	}
+}
+
+func TestNewContextAttachesToErrors(t *testing.T) {
+	ctx := NewContext(context.Background(), "req-1")
+	err := errors.EC(ctx, "service.NewOrder", "boom")
+	if got := errors.LookupString(err, errors.RequestIDField); got != "req-1" {
+		t.Errorf("request ID = %q, want req-1", got)
+	}
+}
+
+func TestGRPCRoundTrip(t *testing.T) {
+	md := metadata.MD{}
+	GRPCClientBefore(NewContext(context.Background(), "req-1"), &md)
+	if got, _ := FromContext(GRPCServerBefore(context.Background(), md)); got != "req-1" {
+		t.Errorf("server got request ID %q, want req-1", got)
+	}
+
+	md = metadata.MD{}
+	ctx := GRPCClientBefore(context.Background(), &md)
+	sent, _ := FromContext(ctx)
+	if got := md.Get(MetadataKey); len(got) != 1 || got[0] != sent || sent == "" {
+		t.Errorf("sent %v, want the generated %q", got, sent)
+	}
+}
+
+func TestHTTPRoundTrip(t *testing.T) {
+	r := httptest.NewRequest("POST", "/orders", nil)
+	HTTPClientBefore(NewContext(context.Background(), "req-1"), r)
+	if got, _ := FromContext(HTTPServerBefore(context.Background(), r)); got != "req-1" {
+		t.Errorf("server got request ID %q, want req-1", got)
+	}
+
+	r = httptest.NewRequest("POST", "/orders", nil)
+	if got, _ := FromContext(HTTPServerBefore(context.Background(), r)); got == "" {
+		t.Error("server didn't generate a request ID")
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "req-1"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/pb.Orders/NewOrder"}
+
+	var seen string
+	_, err := UnaryServerInterceptor()(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		seen, _ = FromContext(ctx)
+		return nil, errors.E("service.NewOrder", "boom")
+	})
+	if seen != "req-1" {
+		t.Errorf("handler got request ID %q, want req-1", seen)
+	}
+	if got := errors.LookupString(err, errors.RequestIDField); got != "req-1" {
+		t.Errorf("request ID = %q, want req-1", got)
+	}
+	if got, want := errors.Ops(err), []errors.Op{"service.NewOrder"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ops = %v, want %v", got, want)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var sent []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		sent = md.Get(MetadataKey)
+		return nil
+	}
+
+	ctx := NewContext(context.Background(), "req-1")
+	UnaryClientInterceptor()(ctx, "/pb.Orders/NewOrder", nil, nil, nil, invoker)
+	if len(sent) != 1 || sent[0] != "req-1" {
+		t.Errorf("sent %v, want [req-1]", sent)
+	}
+
+	UnaryClientInterceptor()(context.Background(), "/pb.Orders/NewOrder", nil, nil, nil, invoker)
+	if len(sent) != 1 || sent[0] == "" {
+		t.Errorf("sent %v, want a generated ID", sent)
+	}
+}